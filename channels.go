@@ -106,6 +106,13 @@ func (c *Client) GetChannel(ctx context.Context, typ string, channelID string) (
 	return channel, resp, nil
 }
 
+// GetChannelStream fetches a channel stream, which contains all of the services on the channel.
+func (c *Client) GetChannelStream(ctx context.Context, typ string, channel string, decode bool) (io.ReadCloser, *http.Response, error) {
+	u := fmt.Sprintf("channels/%s/%s/stream", typ, channel)
+
+	return c.getTS(ctx, u, decode)
+}
+
 // ChannelsConfig represents a Mirakurun channels config.
 type ChannelsConfig []*ChannelConfig
 
@@ -142,6 +149,7 @@ func (c *Client) UpdateChannelsConfig(ctx context.Context, body ChannelsConfig)
 	if err != nil {
 		return nil, nil, err
 	}
+	markIdempotent(req)
 
 	var channels ChannelsConfig
 	resp, err := c.Do(ctx, req, &channels)
@@ -172,7 +180,7 @@ func (c *Client) ChannelScan(ctx context.Context, opt *ChannelScanOptions) (io.R
 	}
 
 	req = req.WithContext(ctx)
-	resp, err := c.client.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -99,6 +99,39 @@ func TestClient_GetChannel(t *testing.T) {
 	}
 }
 
+func TestClient_GetChannelStream(t *testing.T) {
+	var decode string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/channels/GR/16/stream", func(w http.ResponseWriter, r *http.Request) {
+		decode = r.URL.Query().Get("decode")
+		w.Header().Set("Content-Type", "video/MP2T")
+		io.WriteString(w, "\x47")
+	})
+	mux.HandleFunc("/api/channels/GR/27/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "{}")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	stream, _, err := c.GetChannelStream(context.Background(), "GR", "16", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	if got, want := decode, "1"; got != want {
+		t.Errorf("decode is %v, want %v", got, want)
+	}
+
+	if _, _, err := c.GetChannelStream(context.Background(), "GR", "27", false); err == nil {
+		t.Error("request should returns error for invalid content type")
+	}
+}
+
 func TestClient_GetChannelsConfig(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/config/channels", func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import "strconv"
+
+// VideoFormat represents the video attributes decoded from an ARIB STD-B10
+// component descriptor.
+type VideoFormat struct {
+	Codec       string // "mpeg2", "h.264" or "h.265"
+	Height      int    // the number of effective lines, such as 1080
+	Progressive bool
+	FrameRate   float64
+
+	// AspectRatio is "4:3", "16:9" or ">16:9".
+	AspectRatio string
+	PanVectors  bool
+}
+
+// Resolution returns the resolution such as "1080i" or "720p".
+func (f VideoFormat) Resolution() string {
+	scan := "i"
+	if f.Progressive {
+		scan = "p"
+	}
+
+	return strconv.Itoa(f.Height) + scan
+}
+
+var videoCodecs = map[int]string{
+	0x01: "mpeg2",
+	0x05: "h.264",
+	0x09: "h.265",
+}
+
+var videoScans = map[int]struct {
+	height      int
+	progressive bool
+	frameRate   float64
+}{
+	0x0: {480, false, 29.97},
+	0x9: {2160, true, 59.94},
+	0xA: {480, true, 59.94},
+	0xB: {1080, false, 29.97},
+	0xC: {720, true, 59.94},
+	0xD: {240, true, 15},
+	0xE: {1080, true, 59.94},
+	0xF: {180, true, 15},
+}
+
+var videoAspectRatios = map[int]struct {
+	ratio      string
+	panVectors bool
+}{
+	0x1: {"4:3", false},
+	0x2: {"16:9", true},
+	0x3: {"16:9", false},
+	0x4: {">16:9", false},
+}
+
+// Format decodes the stream_content and component_type values of the video.
+// It returns false if they are not defined by ARIB STD-B10.
+func (v ProgramVideo) Format() (VideoFormat, bool) {
+	codec, ok := videoCodecs[v.StreamContent]
+	if !ok {
+		return VideoFormat{}, false
+	}
+
+	scan, ok := videoScans[v.ComponentType>>4]
+	if !ok {
+		return VideoFormat{}, false
+	}
+
+	aspect, ok := videoAspectRatios[v.ComponentType&0xF]
+	if !ok {
+		return VideoFormat{}, false
+	}
+
+	return VideoFormat{
+		Codec:       codec,
+		Height:      scan.height,
+		Progressive: scan.progressive,
+		FrameRate:   scan.frameRate,
+		AspectRatio: aspect.ratio,
+		PanVectors:  aspect.panVectors,
+	}, true
+}
+
+// AudioFormat represents the audio attributes decoded from an ARIB STD-B10
+// component_type value of MPEG-2 AAC.
+type AudioFormat struct {
+	// Mode is the name of the audio mode, such as "stereo", "dual mono" or "5.1ch".
+	Mode string
+
+	// ChannelLayout is the arrangement of the channels, such as "3/2.1".
+	ChannelLayout string
+
+	// Channels is the number of channels including the LFE channels.
+	Channels int
+	LFE      int
+
+	// Bilingual is set for the dual mono mode, which usually carries two languages.
+	Bilingual bool
+
+	DialogControl    bool
+	VisuallyImpaired bool
+	HearingImpaired  bool
+}
+
+var audioModes = map[int]struct {
+	mode     string
+	layout   string
+	channels int
+	lfe      int
+}{
+	0x01: {"mono", "1/0", 1, 0},
+	0x02: {"dual mono", "1/0+1/0", 2, 0},
+	0x03: {"stereo", "2/0", 2, 0},
+	0x04: {"3ch", "2/1", 3, 0},
+	0x05: {"3ch", "3/0", 3, 0},
+	0x06: {"4ch", "2/2", 4, 0},
+	0x07: {"4ch", "3/1", 4, 0},
+	0x08: {"5ch", "3/2", 5, 0},
+	0x09: {"5.1ch", "3/2.1", 6, 1},
+	0x0A: {"6.1ch", "3/3.1", 7, 1},
+	0x0B: {"6.1ch", "2/0/0-2/0/2-0.1", 7, 1},
+	0x0C: {"7.1ch", "5/2.1", 8, 1},
+	0x0D: {"7.1ch", "3/2/2.1", 8, 1},
+	0x0E: {"7.1ch", "2/0/0-3/0/2-0.1", 8, 1},
+	0x0F: {"7.2ch", "0/2/0-3/0/2-0.2", 9, 2},
+	0x10: {"10.2ch", "2/0/0-3/2/3-0.2", 12, 2},
+	0x11: {"22.2ch", "3/3/3-5/2/3-3/0/0.2", 24, 2},
+}
+
+// Format decodes the component_type value of the audio, whose highest bit is the
+// dialog control flag, the next two bits are the audio for the handicapped and
+// the other bits are the audio mode. It returns false if the mode is not defined
+// by ARIB STD-B10.
+func (a ProgramAudio) Format() (AudioFormat, bool) {
+	mode, ok := audioModes[a.ComponentType&0x1F]
+	if !ok {
+		return AudioFormat{}, false
+	}
+
+	handicapped := a.ComponentType >> 5 & 0x3
+
+	return AudioFormat{
+		Mode:             mode.mode,
+		ChannelLayout:    mode.layout,
+		Channels:         mode.channels,
+		LFE:              mode.lfe,
+		Bilingual:        a.ComponentType&0x1F == 0x02,
+		DialogControl:    a.ComponentType&0x80 != 0,
+		VisuallyImpaired: handicapped == 0x1,
+		HearingImpaired:  handicapped == 0x2,
+	}, true
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import "testing"
+
+func TestProgramVideo_Format(t *testing.T) {
+	tests := []struct {
+		video      ProgramVideo
+		resolution string
+		want       VideoFormat
+	}{
+		{ProgramVideo{StreamContent: 0x01, ComponentType: 0xB3}, "1080i", VideoFormat{"mpeg2", 1080, false, 29.97, "16:9", false}},
+		{ProgramVideo{StreamContent: 0x01, ComponentType: 0x01}, "480i", VideoFormat{"mpeg2", 480, false, 29.97, "4:3", false}},
+		{ProgramVideo{StreamContent: 0x01, ComponentType: 0xA2}, "480p", VideoFormat{"mpeg2", 480, true, 59.94, "16:9", true}},
+		{ProgramVideo{StreamContent: 0x05, ComponentType: 0xC4}, "720p", VideoFormat{"h.264", 720, true, 59.94, ">16:9", false}},
+		{ProgramVideo{StreamContent: 0x05, ComponentType: 0xD3}, "240p", VideoFormat{"h.264", 240, true, 15, "16:9", false}},
+		{ProgramVideo{StreamContent: 0x09, ComponentType: 0x93}, "2160p", VideoFormat{"h.265", 2160, true, 59.94, "16:9", false}},
+	}
+
+	for _, test := range tests {
+		got, ok := test.video.Format()
+		if !ok {
+			t.Errorf("format of %+v is not defined", test.video)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("format of %+v is %+v, want %+v", test.video, got, test.want)
+		}
+
+		if got, want := got.Resolution(), test.resolution; got != want {
+			t.Errorf("resolution of %+v is %v, want %v", test.video, got, want)
+		}
+	}
+
+	for _, video := range []ProgramVideo{{StreamContent: 0x02, ComponentType: 0xB3}, {StreamContent: 0x01, ComponentType: 0xB5}} {
+		if _, ok := video.Format(); ok {
+			t.Errorf("format of %+v should not be defined", video)
+		}
+	}
+}
+
+func TestProgramAudio_Format(t *testing.T) {
+	tests := []struct {
+		audio ProgramAudio
+		want  AudioFormat
+	}{
+		{ProgramAudio{ComponentType: 0x01}, AudioFormat{Mode: "mono", ChannelLayout: "1/0", Channels: 1}},
+		{ProgramAudio{ComponentType: 0x02}, AudioFormat{Mode: "dual mono", ChannelLayout: "1/0+1/0", Channels: 2, Bilingual: true}},
+		{ProgramAudio{ComponentType: 0x03}, AudioFormat{Mode: "stereo", ChannelLayout: "2/0", Channels: 2}},
+		{ProgramAudio{ComponentType: 0x09}, AudioFormat{Mode: "5.1ch", ChannelLayout: "3/2.1", Channels: 6, LFE: 1}},
+		{ProgramAudio{ComponentType: 0x11}, AudioFormat{Mode: "22.2ch", ChannelLayout: "3/3/3-5/2/3-3/0/0.2", Channels: 24, LFE: 2}},
+		{ProgramAudio{ComponentType: 0x23}, AudioFormat{Mode: "stereo", ChannelLayout: "2/0", Channels: 2, VisuallyImpaired: true}},
+		{ProgramAudio{ComponentType: 0xC3}, AudioFormat{Mode: "stereo", ChannelLayout: "2/0", Channels: 2, DialogControl: true, HearingImpaired: true}},
+	}
+
+	for _, test := range tests {
+		got, ok := test.audio.Format()
+		if !ok {
+			t.Errorf("format of %+v is not defined", test.audio)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("format of %+v is %+v, want %+v", test.audio, got, test.want)
+		}
+	}
+
+	if _, ok := (ProgramAudio{ComponentType: 0x00}).Format(); ok {
+		t.Error("format of component type 0x00 should not be defined")
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+type contextKey int
+
+const (
+	userAgentContextKey contextKey = iota
+	priorityContextKey
+	noRetryContextKey
+)
+
+// ContextWithUserAgent returns a copy of ctx in which requests are sent with the application
+// name userAgent instead of Client.UserAgent.
+func ContextWithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, userAgentContextKey, userAgent)
+}
+
+// UserAgentFromContext returns the application name stored in ctx by ContextWithUserAgent.
+func UserAgentFromContext(ctx context.Context) (string, bool) {
+	userAgent, ok := ctx.Value(userAgentContextKey).(string)
+	return userAgent, ok
+}
+
+// ContextWithPriority returns a copy of ctx in which requests are sent with priority
+// instead of Client.Priority. Mirakurun prefers tuner users with higher priorities,
+// and negative priorities never take over a tuner in use.
+func ContextWithPriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, priorityContextKey, priority)
+}
+
+// PriorityFromContext returns the priority stored in ctx by ContextWithPriority.
+func PriorityFromContext(ctx context.Context) (int, bool) {
+	priority, ok := ctx.Value(priorityContextKey).(int)
+	return priority, ok
+}
+
+// withoutRetry returns a copy of ctx in which Client.Do does not follow
+// Client.RetryPolicy, for the callers retrying by themselves.
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryContextKey, true)
+}
+
+// withContextHeaders returns req, or a copy of it whose headers are overridden by
+// the values stored in its context.
+func withContextHeaders(req *http.Request) *http.Request {
+	ctx := req.Context()
+
+	app, hasUserAgent := UserAgentFromContext(ctx)
+	priority, hasPriority := PriorityFromContext(ctx)
+	if !hasUserAgent && !hasPriority {
+		return req
+	}
+
+	req = req.Clone(ctx)
+	if hasUserAgent {
+		req.Header.Set("User-Agent", userAgent(app))
+	}
+	if hasPriority {
+		req.Header.Set("X-Mirakurun-Priority", strconv.Itoa(priority))
+	}
+
+	return req
+}
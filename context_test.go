@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestContextWithUserAgent(t *testing.T) {
+	var agent string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		agent = r.UserAgent()
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.UserAgent = "recorder/1.0 (scheduler)"
+
+	ctx := ContextWithUserAgent(context.Background(), "recorder/1.0 (viewer)")
+	stream, _, err := c.GetEventsStream(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	if got, want := agent, "recorder/1.0 (viewer) go-mirakurun/1.0 Go/"+goVersion; got != want {
+		t.Errorf("user agent is %v, want %v", got, want)
+	}
+}
+
+func TestContextWithPriority(t *testing.T) {
+	var priority string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/services/3239123608/stream", func(w http.ResponseWriter, r *http.Request) {
+		priority = r.Header.Get("X-Mirakurun-Priority")
+		w.Header().Set("Content-Type", "video/MP2T")
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/api/tuners", func(w http.ResponseWriter, r *http.Request) {
+		priority = r.Header.Get("X-Mirakurun-Priority")
+		http.ServeFile(w, r, "testdata/tuners.json")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.Priority = 2
+
+	if _, _, err := c.GetTuners(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := priority, "2"; got != want {
+		t.Errorf("priority is %v, want %v", got, want)
+	}
+
+	ctx := ContextWithPriority(context.Background(), -1)
+	stream, _, err := c.GetServiceStream(ctx, 3239123608, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.Close()
+
+	if got, want := priority, "-1"; got != want {
+		t.Errorf("priority is %v, want %v", got, want)
+	}
+}
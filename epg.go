@@ -0,0 +1,348 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EPG is an in-memory store of programs with indexed queries.
+// It is safe for concurrent use.
+type EPG struct {
+	mu sync.RWMutex
+
+	programs  map[int]*Program
+	all       []*Program
+	byService map[int][]*Program
+	bySeries  map[int]map[int]*Program
+	byGenre   map[int]map[int]*Program
+	maxLength time.Duration
+
+	// updatedAt is the time when the programs are fetched or the last event is applied.
+	updatedAt time.Time
+}
+
+// NewEPG returns a new EPG storing programs.
+func NewEPG(programs []*Program) *EPG {
+	e := &EPG{
+		programs:  map[int]*Program{},
+		byService: map[int][]*Program{},
+		bySeries:  map[int]map[int]*Program{},
+		byGenre:   map[int]map[int]*Program{},
+	}
+
+	for _, p := range programs {
+		e.put(p)
+	}
+
+	return e
+}
+
+// loadMargin is subtracted from the time of the programs response, as the programs
+// may be read before the response is dated. Replaying a few more events is harmless.
+const loadMargin = 5 * time.Second
+
+// LoadEPG fetches the programs and returns an EPG storing them.
+func (c *Client) LoadEPG(ctx context.Context, opt *ProgramsListOptions) (*EPG, *http.Response, error) {
+	programs, resp, err := c.GetPrograms(ctx, opt)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	e := NewEPG(programs)
+	e.updatedAt = responseTime(resp).Add(-loadMargin)
+
+	return e, resp, nil
+}
+
+// Sync keeps the EPG up to date with the program events of c until ctx is done.
+//
+// The events which occurred since the EPG is returned by LoadEPG, or since the
+// last event applied, are replayed first, so that the EPG misses no update.
+func (e *EPG) Sync(ctx context.Context, c *Client) error {
+	e.mu.RLock()
+	since := e.updatedAt
+	e.mu.RUnlock()
+
+	s := NewSubscriber(c, &EventsListOptions{Resource: "program"})
+	s.Since = since
+
+	return s.Run(ctx, e.Apply)
+}
+
+// Apply updates the EPG with a program event.
+func (e *EPG) Apply(event *Event) {
+	program := event.Program()
+	if program == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if event.Time.After(e.updatedAt) {
+		e.updatedAt = event.Time.Time
+	}
+
+	switch event.Type {
+	case "create", "update":
+		e.put(program)
+	case "remove":
+		e.remove(program.ID)
+	}
+}
+
+// Len returns the number of the programs.
+func (e *EPG) Len() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return len(e.programs)
+}
+
+// Program returns the program for the Mirakurun program ID id, or nil if not found.
+func (e *EPG) Program(id int) *Program {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.programs[id]
+}
+
+// Service returns the programs of the service with the Mirakurun service ID id
+// in order of start time.
+func (e *EPG) Service(id int) []*Program {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return append([]*Program(nil), e.byService[id]...)
+}
+
+// Between returns the programs on air at any time in [start, end) in order of start time.
+func (e *EPG) Between(start, end time.Time) []*Program {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.between(e.all, start, end)
+}
+
+// ServiceBetween returns the programs of the service with the Mirakurun service ID id
+// on air at any time in [start, end) in order of start time.
+func (e *EPG) ServiceBetween(id int, start, end time.Time) []*Program {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.between(e.byService[id], start, end)
+}
+
+// NowNext returns the program of the service with the Mirakurun service ID id
+// on air at t, and the program following it. Either of them is nil if not found.
+func (e *EPG) NowNext(id int, t time.Time) (*Program, *Program) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	programs := e.byService[id]
+	i := sort.Search(len(programs), func(i int) bool {
+		return programs[i].StartAt.After(t)
+	})
+
+	var now, next *Program
+	if i > 0 && programs[i-1].IsAiringAt(t) {
+		now = programs[i-1]
+	}
+	if i < len(programs) {
+		next = programs[i]
+	}
+
+	return now, next
+}
+
+// Genre returns the programs having the genre g in order of start time.
+func (e *EPG) Genre(g Genre) []*Program {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return sortPrograms(e.byGenre[int(g)])
+}
+
+// Series returns the programs of the series with the ID id in order of start time.
+func (e *EPG) Series(id int) []*Program {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return sortPrograms(e.bySeries[id])
+}
+
+// Search returns the programs whose name or full description contains text,
+// ignoring case, in order of start time.
+func (e *EPG) Search(text string) []*Program {
+	text = strings.ToLower(text)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var programs []*Program
+	for _, p := range e.all {
+		if containsText(p, text) {
+			programs = append(programs, p)
+		}
+	}
+
+	return programs
+}
+
+func containsText(p *Program, text string) bool {
+	return strings.Contains(strings.ToLower(p.Name), text) ||
+		strings.Contains(strings.ToLower(p.FullDescription()), text)
+}
+
+func (e *EPG) between(programs []*Program, start, end time.Time) []*Program {
+	from := sort.Search(len(programs), func(i int) bool {
+		return !programs[i].StartAt.Before(start.Add(-e.maxLength))
+	})
+
+	var result []*Program
+	for _, p := range programs[from:] {
+		if !p.StartAt.Before(end) {
+			break
+		}
+
+		if p.EndAt().After(start) {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+func (e *EPG) put(p *Program) {
+	e.remove(p.ID)
+
+	e.programs[p.ID] = p
+	e.all = insertProgram(e.all, p)
+
+	sid := p.ServiceItemID()
+	e.byService[sid] = insertProgram(e.byService[sid], p)
+
+	if p.Series.ID != 0 {
+		addProgram(e.bySeries, p.Series.ID, p)
+	}
+
+	for _, genre := range p.Genres {
+		addProgram(e.byGenre, int(genre.Genre()), p)
+		if ext, ok := genre.ExtendedGenre(); ok {
+			addProgram(e.byGenre, int(ext.Genre()), p)
+		}
+	}
+
+	if length := p.Length(); length > e.maxLength {
+		e.maxLength = length
+	}
+}
+
+func (e *EPG) remove(id int) {
+	p, ok := e.programs[id]
+	if !ok {
+		return
+	}
+
+	delete(e.programs, id)
+	e.all = removeProgram(e.all, p)
+
+	sid := p.ServiceItemID()
+	if e.byService[sid] = removeProgram(e.byService[sid], p); len(e.byService[sid]) == 0 {
+		delete(e.byService, sid)
+	}
+
+	deleteProgram(e.bySeries, p.Series.ID, p)
+	for _, genre := range p.Genres {
+		deleteProgram(e.byGenre, int(genre.Genre()), p)
+		if ext, ok := genre.ExtendedGenre(); ok {
+			deleteProgram(e.byGenre, int(ext.Genre()), p)
+		}
+	}
+}
+
+func programLess(a, b *Program) bool {
+	if !a.StartAt.Equal(b.StartAt.Time) {
+		return a.StartAt.Before(b.StartAt.Time)
+	}
+
+	return a.ID < b.ID
+}
+
+func insertProgram(programs []*Program, p *Program) []*Program {
+	i := sort.Search(len(programs), func(i int) bool {
+		return programLess(p, programs[i])
+	})
+
+	programs = append(programs, nil)
+	copy(programs[i+1:], programs[i:])
+	programs[i] = p
+
+	return programs
+}
+
+func removeProgram(programs []*Program, p *Program) []*Program {
+	i := sort.Search(len(programs), func(i int) bool {
+		return !programLess(programs[i], p)
+	})
+
+	if i < len(programs) && programs[i].ID == p.ID {
+		programs = append(programs[:i], programs[i+1:]...)
+	}
+
+	return programs
+}
+
+func addProgram(index map[int]map[int]*Program, key int, p *Program) {
+	if index[key] == nil {
+		index[key] = map[int]*Program{}
+	}
+
+	index[key][p.ID] = p
+}
+
+func deleteProgram(index map[int]map[int]*Program, key int, p *Program) {
+	delete(index[key], p.ID)
+	if len(index[key]) == 0 {
+		delete(index, key)
+	}
+}
+
+func sortPrograms(set map[int]*Program) []*Program {
+	programs := make([]*Program, 0, len(set))
+	for _, p := range set {
+		programs = append(programs, p)
+	}
+
+	sort.Slice(programs, func(i, j int) bool {
+		return programLess(programs[i], programs[j])
+	})
+
+	return programs
+}
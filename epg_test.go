@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func testEPGPrograms() []*Program {
+	base := time.Date(2018, 1, 21, 7, 0, 0, 0, JST)
+
+	return []*Program{
+		{ID: 1, NetworkID: 4, ServiceID: 103, StartAt: NewTimestamp(base), Duration: Duration{30 * time.Minute}, Name: "News", Genres: []ProgramGenre{{Level1: 0x0}}},
+		{ID: 2, NetworkID: 4, ServiceID: 103, StartAt: NewTimestamp(base.Add(30 * time.Minute)), Duration: Duration{25 * time.Minute}, Name: "Cardcaptor Sakura: Clear Card ep. 3", Series: ProgramSeries{ID: 100}, Genres: []ProgramGenre{{Level1: 0x7}}},
+		{ID: 3, NetworkID: 4, ServiceID: 103, StartAt: NewTimestamp(base.Add(55 * time.Minute)), Duration: Duration{5 * time.Minute}, Name: "Weather", Description: "Tomorrow's forecast"},
+		{ID: 4, NetworkID: 4, ServiceID: 101, StartAt: NewTimestamp(base.Add(-2 * time.Hour)), Duration: Duration{3 * time.Hour}, Name: "Movie", Genres: []ProgramGenre{{Level1: 0x6}}},
+		{ID: 5, NetworkID: 4, ServiceID: 101, StartAt: NewTimestamp(base.Add(7 * 24 * time.Hour)), Duration: Duration{25 * time.Minute}, Name: "Cardcaptor Sakura: Clear Card ep. 4", Series: ProgramSeries{ID: 100}, Genres: []ProgramGenre{{Level1: 0x7}}},
+	}
+}
+
+func programIDs(programs []*Program) []int {
+	ids := make([]int, len(programs))
+	for i, p := range programs {
+		ids[i] = p.ID
+	}
+
+	return ids
+}
+
+func assertProgramIDs(t *testing.T, name string, programs []*Program, want ...int) {
+	t.Helper()
+
+	got := programIDs(programs)
+	if len(got) != len(want) {
+		t.Errorf("%s is %v, want %v", name, got, want)
+		return
+	}
+
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s is %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func TestClient_LoadEPG(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/programs", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/programs.json")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	epg, _, err := c.LoadEPG(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if epg.Len() < 1 {
+		t.Fatal("epg is empty")
+	}
+
+	if p := epg.Program(40010310979); p == nil {
+		t.Error("program 40010310979 is not found")
+	} else if got, want := p.Name, "Cardcaptor Sakura: Clear Card ep. 3"; got != want {
+		t.Errorf("program name is %v, want %v", got, want)
+	}
+}
+
+func TestEPG(t *testing.T) {
+	epg := NewEPG(testEPGPrograms())
+	base := time.Date(2018, 1, 21, 7, 0, 0, 0, JST)
+
+	if got, want := epg.Len(), 5; got != want {
+		t.Errorf("length is %v, want %v", got, want)
+	}
+
+	assertProgramIDs(t, "service 400103", epg.Service(400103), 1, 2, 3)
+	assertProgramIDs(t, "service 400101", epg.Service(400101), 4, 5)
+	assertProgramIDs(t, "between", epg.Between(base.Add(20*time.Minute), base.Add(time.Hour)), 4, 1, 2, 3)
+	assertProgramIDs(t, "between", epg.Between(base.Add(time.Hour), base.Add(2*time.Hour)))
+	assertProgramIDs(t, "service 400103 between", epg.ServiceBetween(400103, base.Add(30*time.Minute), base.Add(55*time.Minute)), 2)
+	assertProgramIDs(t, "anime", epg.Genre(GenreAnime), 2, 5)
+	assertProgramIDs(t, "series 100", epg.Series(100), 2, 5)
+	assertProgramIDs(t, "search", epg.Search("cardcaptor"), 2, 5)
+	assertProgramIDs(t, "search", epg.Search("FORECAST"), 3)
+}
+
+func TestEPG_NowNext(t *testing.T) {
+	epg := NewEPG(testEPGPrograms())
+	base := time.Date(2018, 1, 21, 7, 0, 0, 0, JST)
+
+	tests := []struct {
+		t    time.Time
+		now  int
+		next int
+	}{
+		{base.Add(-time.Minute), 0, 1},
+		{base, 1, 2},
+		{base.Add(54 * time.Minute), 2, 3},
+		{base.Add(time.Hour), 0, 0},
+	}
+
+	for _, test := range tests {
+		now, next := epg.NowNext(400103, test.t)
+
+		if now != nil {
+			assertProgramIDs(t, "now at "+test.t.String(), []*Program{now}, test.now)
+		} else if test.now != 0 {
+			t.Errorf("now at %v is nil, want %v", test.t, test.now)
+		}
+
+		if next != nil {
+			assertProgramIDs(t, "next at "+test.t.String(), []*Program{next}, test.next)
+		} else if test.next != 0 {
+			t.Errorf("next at %v is nil, want %v", test.t, test.next)
+		}
+	}
+}
+
+func TestEPG_Apply(t *testing.T) {
+	epg := NewEPG(testEPGPrograms())
+	base := time.Date(2018, 1, 21, 7, 0, 0, 0, JST)
+
+	epg.Apply(&Event{Resource: "program", Type: "update", Data: &Program{ID: 2, NetworkID: 4, ServiceID: 103, StartAt: NewTimestamp(base.Add(35 * time.Minute)), Duration: Duration{20 * time.Minute}, Name: "Special"}})
+	epg.Apply(&Event{Resource: "program", Type: "create", Data: &Program{ID: 6, NetworkID: 4, ServiceID: 103, StartAt: NewTimestamp(base.Add(time.Hour)), Duration: Duration{time.Hour}, Name: "Drama"}})
+	epg.Apply(&Event{Resource: "program", Type: "remove", Data: &Program{ID: 1}})
+	epg.Apply(&Event{Resource: "service", Type: "update", Data: &Service{ID: 400103}})
+
+	if got, want := epg.Len(), 5; got != want {
+		t.Errorf("length is %v, want %v", got, want)
+	}
+
+	assertProgramIDs(t, "service 400103", epg.Service(400103), 2, 3, 6)
+	assertProgramIDs(t, "series 100", epg.Series(100), 5)
+	assertProgramIDs(t, "anime", epg.Genre(GenreAnime), 5)
+	assertProgramIDs(t, "news", epg.Genre(GenreNews))
+	assertProgramIDs(t, "search", epg.Search("special"), 2)
+}
+
+func TestEPG_Sync(t *testing.T) {
+	loadedAt := time.Unix(1516487400, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/programs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", loadedAt.UTC().Format(http.TimeFormat))
+		http.ServeFile(w, r, "testdata/programs.json")
+	})
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		before := loadedAt.Add(-time.Minute).UnixNano() / int64(time.Millisecond)
+		after := loadedAt.Add(time.Second).UnixNano() / int64(time.Millisecond)
+		fmt.Fprintf(w, `[
+			{"resource":"program","type":"remove","data":{"id":40010310979},"time":%d},
+			{"resource":"program","type":"create","data":{"id":1,"networkId":4,"serviceId":103,"name":"Created after loading"},"time":%d}
+		]`, before, after)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	epg, _, err := c.LoadEPG(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- epg.Sync(ctx, c)
+	}()
+
+	for epg.Program(1) == nil && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if p := epg.Program(1); p == nil {
+		t.Error("program created after loading is not found")
+	}
+
+	if p := epg.Program(40010310979); p == nil {
+		t.Error("program removed before loading is removed")
+	}
+}
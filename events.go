@@ -23,12 +23,19 @@
 package mirakurun
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"time"
 )
 
 // Event represents a Mirakurun event.
+//
+// Data holds a *Program, *Service or *TunerDevice for the "program", "service"
+// and "tuner" resources respectively, and a json.RawMessage for the others.
 type Event struct {
 	Resource string      `json:"resource"`
 	Type     string      `json:"type"`
@@ -36,6 +43,79 @@ type Event struct {
 	Time     Timestamp   `json:"time"`
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	return e.unmarshal(data, false)
+}
+
+// strictEvent is an Event whose fields and data must be known, for Client.Strict.
+type strictEvent Event
+
+func (e *strictEvent) UnmarshalJSON(data []byte) error {
+	return (*Event)(e).unmarshal(data, true)
+}
+
+func unmarshal(data []byte, v interface{}, strict bool) error {
+	if !strict {
+		return json.Unmarshal(data, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	return dec.Decode(v)
+}
+
+func (e *Event) unmarshal(data []byte, strict bool) error {
+	type event Event
+	var v struct {
+		*event
+		Data json.RawMessage `json:"data"`
+	}
+	v.event = (*event)(e)
+
+	if err := unmarshal(data, &v, strict); err != nil {
+		return err
+	}
+
+	switch e.Resource {
+	case "program":
+		e.Data = new(Program)
+	case "service":
+		e.Data = new(Service)
+	case "tuner":
+		e.Data = new(TunerDevice)
+	default:
+		e.Data = v.Data
+		return nil
+	}
+
+	if len(v.Data) == 0 || string(v.Data) == "null" {
+		e.Data = nil
+		return nil
+	}
+
+	return unmarshal(v.Data, e.Data, strict)
+}
+
+// Program returns the program of a "program" event, or nil for the other events.
+func (e *Event) Program() *Program {
+	program, _ := e.Data.(*Program)
+	return program
+}
+
+// Service returns the service of a "service" event, or nil for the other events.
+func (e *Event) Service() *Service {
+	service, _ := e.Data.(*Service)
+	return service
+}
+
+// Tuner returns the tuner of a "tuner" event, or nil for the other events.
+func (e *Event) Tuner() *TunerDevice {
+	tuner, _ := e.Data.(*TunerDevice)
+	return tuner
+}
+
 // EventsListOptions specifies the optional parameters to the Client.GetEventsStream method.
 type EventsListOptions struct {
 	Resource string `url:"resource,omitempty"`
@@ -49,6 +129,21 @@ func (c *Client) GetEvents(ctx context.Context) ([]*Event, *http.Response, error
 		return nil, nil, err
 	}
 
+	if c.Strict {
+		strictEvents := []*strictEvent{}
+		resp, err := c.Do(ctx, req, &strictEvents)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		events := make([]*Event, len(strictEvents))
+		for i, event := range strictEvents {
+			events[i] = (*Event)(event)
+		}
+
+		return events, resp, nil
+	}
+
 	events := []*Event{}
 	resp, err := c.Do(ctx, req, &events)
 	if err != nil {
@@ -67,3 +162,214 @@ func (c *Client) GetEventsStream(ctx context.Context, opt *EventsListOptions) (i
 
 	return c.requestStream(ctx, "GET", u)
 }
+
+// EventStream decodes events from a Mirakurun events stream, which is a JSON array
+// whose elements are written as the events occur.
+type EventStream struct {
+	ctx    context.Context
+	stream io.ReadCloser
+	reader *eofReader
+	dec    *json.Decoder
+
+	filters []EventFilter
+	strict  bool
+
+	started bool
+	event   *Event
+	err     error
+}
+
+// NewEventStream returns an EventStream reading from stream, such as the one
+// returned by Client.GetEventsStream.
+func NewEventStream(stream io.ReadCloser) *EventStream {
+	reader := &eofReader{r: stream}
+
+	return &EventStream{ctx: context.Background(), stream: stream, reader: reader, dec: json.NewDecoder(reader)}
+}
+
+// eofReader records whether the underlying reader has reached the end.
+type eofReader struct {
+	r   io.Reader
+	eof bool
+}
+
+func (r *eofReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+
+	return n, err
+}
+
+// SubscribeEvents fetches a events stream and returns an EventStream decoding it.
+// The stream ends when ctx is canceled. The events are decoded following Client.Strict.
+func (c *Client) SubscribeEvents(ctx context.Context, opt *EventsListOptions) (*EventStream, *http.Response, error) {
+	stream, resp, err := c.GetEventsStream(ctx, opt)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	s := NewEventStream(stream)
+	s.ctx = ctx
+	s.strict = c.Strict
+
+	return s, resp, nil
+}
+
+// Next decodes the next event, which will then be available through the Event method.
+// It returns false when the stream ends, either by reaching the end or an error.
+// After Next returns false, the Err method will return any error that occurred.
+func (s *EventStream) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	if !s.started {
+		tok, err := s.dec.Token()
+		if err != nil {
+			s.fail(err)
+			return false
+		}
+
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			s.err = errors.New("mirakurun: events stream does not start with an array")
+			return false
+		}
+
+		s.started = true
+	}
+
+	for s.dec.More() {
+		event := new(Event)
+		var v interface{} = event
+		if s.strict {
+			v = (*strictEvent)(event)
+		}
+
+		if err := s.dec.Decode(v); err != nil {
+			s.fail(err)
+			return false
+		}
+
+		if matchEvent(event, s.filters) {
+			s.event = event
+			return true
+		}
+	}
+
+	s.fail(io.EOF)
+	return false
+}
+
+// Filter makes Next skip events that do not match all of filters, and returns s.
+func (s *EventStream) Filter(filters ...EventFilter) *EventStream {
+	s.filters = append(s.filters, filters...)
+	return s
+}
+
+func (s *EventStream) fail(err error) {
+	s.event = nil
+
+	// Mirakurun never closes the array, so the stream ends with a separator or
+	// an unterminated element, which the decoder reports as a syntax error.
+	var syntaxError *json.SyntaxError
+	switch {
+	case s.ctx.Err() != nil:
+		s.err = s.ctx.Err()
+	case err == io.EOF:
+		s.err = io.EOF
+	case s.reader.eof && (err == io.ErrUnexpectedEOF || errors.As(err, &syntaxError)):
+		s.err = io.EOF
+	default:
+		s.err = err
+	}
+}
+
+// Event returns the event decoded by the last call to Next.
+func (s *EventStream) Event() *Event {
+	return s.event
+}
+
+// Err returns the error that ended the stream, or nil if the stream reached the end.
+func (s *EventStream) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+
+	return s.err
+}
+
+// Close closes the underlying stream.
+func (s *EventStream) Close() error {
+	return s.stream.Close()
+}
+
+// An EventFilter reports whether an event should be received.
+type EventFilter func(*Event) bool
+
+func matchEvent(event *Event, filters []EventFilter) bool {
+	for _, f := range filters {
+		if !f(event) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FilterService returns an EventFilter matching the program and service events
+// for the service with the Mirakurun service ID id, such as 3239123608.
+func FilterService(id int) EventFilter {
+	return func(e *Event) bool {
+		if program := e.Program(); program != nil {
+			return program.ServiceItemID() == id
+		}
+
+		if service := e.Service(); service != nil {
+			return service.ID == id
+		}
+
+		return false
+	}
+}
+
+// FilterNetworkID returns an EventFilter matching the program and service events
+// for the original network ID nid.
+func FilterNetworkID(nid int) EventFilter {
+	return func(e *Event) bool {
+		if program := e.Program(); program != nil {
+			return program.NetworkID == nid
+		}
+
+		if service := e.Service(); service != nil {
+			return service.NetworkID == nid
+		}
+
+		return false
+	}
+}
+
+// FilterEventID returns an EventFilter matching the program events for the event ID eid.
+func FilterEventID(eid int) EventFilter {
+	return func(e *Event) bool {
+		program := e.Program()
+		return program != nil && program.EventID == eid
+	}
+}
+
+// FilterTunerIndex returns an EventFilter matching the tuner events for the tuner at index.
+func FilterTunerIndex(index int) EventFilter {
+	return func(e *Event) bool {
+		tuner := e.Tuner()
+		return tuner != nil && tuner.Index == index
+	}
+}
+
+// FilterTime returns an EventFilter matching the events that occurred in [start, end).
+// A zero start or end leaves that side of the window unbounded.
+func FilterTime(start, end time.Time) EventFilter {
+	return func(e *Event) bool {
+		return (start.IsZero() || !e.Time.Before(start)) && (end.IsZero() || e.Time.Before(end))
+	}
+}
@@ -24,11 +24,15 @@ package mirakurun
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestClient_GetEvents(t *testing.T) {
@@ -54,6 +58,40 @@ func TestClient_GetEvents(t *testing.T) {
 	}
 }
 
+func TestClient_GetEvents_strict(t *testing.T) {
+	bogus := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+		if bogus {
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `[{"resource":"program","type":"create","data":{"id":1,"bogus":2},"time":1516487400000}]`)
+			return
+		}
+		http.ServeFile(w, r, "testdata/events.json")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.Strict = true
+
+	events, _, err := c.GetEvents(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) < 1 || events[0].Program() == nil {
+		t.Fatalf("events are %v, want a program event", events)
+	}
+
+	bogus = true
+	_, _, err = c.GetEvents(context.Background())
+	if _, ok := err.(*DecodeError); !ok {
+		t.Errorf("error is %v, want *DecodeError", err)
+	}
+}
+
 func TestClient_GetEventsStream(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
@@ -76,3 +114,182 @@ func TestClient_GetEventsStream(t *testing.T) {
 		t.Errorf("status code is %v, want %v", got, want)
 	}
 }
+
+func TestClient_SubscribeEvents(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[\n")
+		io.WriteString(w, `{"resource":"program","type":"create","data":{"id":40010310979},"time":1516487400000}`+"\n,\n")
+		io.WriteString(w, `{"resource":"tuner","type":"update","data":{"index":0},"time":1516487401000}`+"\n,\n")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	stream, _, err := c.SubscribeEvents(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	var resources []string
+	for stream.Next() {
+		resources = append(resources, stream.Event().Resource)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(resources), 2; got != want {
+		t.Fatalf("events length is %v, want %v", got, want)
+	}
+
+	if got, want := resources[1], "tuner"; got != want {
+		t.Errorf("event resource is %v, want %v", got, want)
+	}
+}
+
+func TestClient_SubscribeEvents_strict(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[\n")
+		io.WriteString(w, `{"resource":"program","type":"create","data":{"id":1,"bogus":2},"time":1516487400000}`+"\n,\n")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.Strict = true
+
+	stream, _, err := c.SubscribeEvents(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	if stream.Next() {
+		t.Fatalf("event %v is decoded, want an error", stream.Event())
+	}
+
+	if stream.Err() == nil {
+		t.Error("unknown field in event data is accepted")
+	}
+}
+
+func TestEvent_UnmarshalJSON(t *testing.T) {
+	data := `[
+		{"resource":"program","type":"create","data":{"id":40010310979,"name":"Cardcaptor Sakura: Clear Card ep. 3"},"time":1516487400000},
+		{"resource":"service","type":"update","data":{"id":3239123608,"name":"TOKYO MX1"},"time":1516487400000},
+		{"resource":"tuner","type":"update","data":{"index":2,"name":"PT3-T1"},"time":1516487400000},
+		{"resource":"unknown","type":"update","data":{"foo":"bar"},"time":1516487400000}
+	]`
+
+	var events []*Event
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := events[0].Program().Name, "Cardcaptor Sakura: Clear Card ep. 3"; got != want {
+		t.Errorf("program name is %v, want %v", got, want)
+	}
+
+	if got, want := events[1].Service().ID, 3239123608; got != want {
+		t.Errorf("service ID is %v, want %v", got, want)
+	}
+
+	if got, want := events[2].Tuner().Index, 2; got != want {
+		t.Errorf("tuner index is %v, want %v", got, want)
+	}
+
+	if events[2].Program() != nil {
+		t.Error("tuner event should not have program")
+	}
+
+	if got, want := string(events[3].Data.(json.RawMessage)), `{"foo":"bar"}`; got != want {
+		t.Errorf("raw data is %v, want %v", got, want)
+	}
+
+	if got, want := events[3].Time.Unix(), int64(1516487400); got != want {
+		t.Errorf("event time is %v, want %v", got, want)
+	}
+}
+
+func TestClient_SubscribeEvents_cancel(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[\n")
+		io.WriteString(w, `{"resource":"program","type":"create","data":{},"time":1516487400000}`+"\n,\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, _, err := c.SubscribeEvents(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	if !stream.Next() {
+		t.Fatal(stream.Err())
+	}
+
+	cancel()
+
+	if stream.Next() {
+		t.Fatal("stream should end after cancellation")
+	}
+
+	if got, want := stream.Err(), context.Canceled; got != want {
+		t.Errorf("error is %v, want %v", got, want)
+	}
+}
+
+func TestEventStream_Filter(t *testing.T) {
+	data := "[\n" +
+		`{"resource":"program","type":"update","data":{"id":323912360802956,"networkId":32391,"serviceId":23608,"eventId":2956},"time":1516487400000}` + "\n,\n" +
+		`{"resource":"program","type":"update","data":{"id":40010310979,"networkId":4,"serviceId":103,"eventId":10979},"time":1516487401000}` + "\n,\n" +
+		`{"resource":"service","type":"update","data":{"id":3239123608,"networkId":32391,"serviceId":23608},"time":1516487402000}` + "\n,\n" +
+		`{"resource":"tuner","type":"update","data":{"index":2},"time":1516487403000}` + "\n,\n" +
+		`{"resource":"tuner","type":"update","data":{"index":1},"time":1516487404000}` + "\n,\n"
+
+	tests := []struct {
+		filters []EventFilter
+		want    []int64
+	}{
+		{[]EventFilter{FilterService(3239123608)}, []int64{1516487400, 1516487402}},
+		{[]EventFilter{FilterNetworkID(4)}, []int64{1516487401}},
+		{[]EventFilter{FilterEventID(2956), FilterService(3239123608)}, []int64{1516487400}},
+		{[]EventFilter{FilterTunerIndex(2)}, []int64{1516487403}},
+		{[]EventFilter{FilterTime(time.Unix(1516487401, 0), time.Unix(1516487403, 0))}, []int64{1516487401, 1516487402}},
+	}
+
+	for i, test := range tests {
+		stream := NewEventStream(io.NopCloser(strings.NewReader(data))).Filter(test.filters...)
+
+		var got []int64
+		for stream.Next() {
+			got = append(got, stream.Event().Time.Unix())
+		}
+
+		if err := stream.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("events of filters %d are %v, want %v", i, got, test.want)
+		}
+	}
+}
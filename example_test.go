@@ -47,6 +47,24 @@ func ExampleNewClient() {
 	fmt.Println("Program count: ", len(programs))
 }
 
+func ExampleNew() {
+	c, err := mirakurun.New(
+		mirakurun.WithBaseURL("http://192.168.0.5:40772/api/"),
+		mirakurun.WithUserAgent("recorder/1.0"),
+		mirakurun.WithTimeout(30*time.Second),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	programs, _, err := c.GetPrograms(context.Background(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("Program count: ", len(programs))
+}
+
 func ExampleClient_GetChannels() {
 	c := mirakurun.NewClient()
 
@@ -84,6 +102,29 @@ func ExampleClient_GetChannel() {
 	fmt.Printf("%s (%s): %s\n", channel.Channel, channel.Type, channel.Name)
 }
 
+func ExampleClient_GetChannelStream() {
+	filename := fmt.Sprintf("/tmp/stream-%d.ts", time.Now().Unix())
+
+	file, err := os.Create(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	c := mirakurun.NewClient()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, _, err := c.GetChannelStream(ctx, "GR", "16", true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stream.Close()
+
+	fmt.Println("output: ", filename)
+	io.Copy(file, stream)
+}
+
 func ExampleClient_GetServicesByChannel() {
 	c := mirakurun.NewClient()
 
@@ -158,8 +199,8 @@ func ExampleClient_GetProgram() {
 
 	if program.Extended != nil {
 		fmt.Println("")
-		for key, value := range program.Extended {
-			fmt.Printf("%s: %s\n", key, value)
+		for _, item := range program.Extended {
+			fmt.Printf("%s: %s\n", item.Key, item.Value)
 		}
 	}
 }
@@ -325,6 +366,45 @@ func ExampleClient_GetEventsStream() {
 	io.Copy(os.Stdout, stream)
 }
 
+func ExampleClient_SubscribeEvents() {
+	c := mirakurun.NewClient()
+
+	stream, _, err := c.SubscribeEvents(context.Background(), &mirakurun.EventsListOptions{Resource: "program"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stream.Close()
+
+	for stream.Next() {
+		event := stream.Event()
+		fmt.Printf("%s: %s\n", event.Resource, event.Type)
+	}
+
+	if err := stream.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleClient_LoadEPG() {
+	c := mirakurun.NewClient()
+	ctx := context.Background()
+
+	epg, _, err := c.LoadEPG(ctx, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go epg.Sync(ctx, c)
+
+	now, next := epg.NowNext(3239123608, time.Now())
+	if now != nil {
+		fmt.Println("Now: " + now.Name)
+	}
+	if next != nil {
+		fmt.Println("Next: " + next.Name)
+	}
+}
+
 func ExampleClient_GetChannelsConfig() {
 	c := mirakurun.NewClient()
 
@@ -429,6 +509,17 @@ func ExampleClient_GetLog() {
 	fmt.Println(buf)
 }
 
+func ExampleClient_GetLogTail() {
+	c := mirakurun.NewClient()
+
+	buf, _, err := c.GetLogTail(context.Background(), 100)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(buf)
+}
+
 func ExampleClient_GetLogStream() {
 	c := mirakurun.NewClient()
 
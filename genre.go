@@ -0,0 +1,540 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import "fmt"
+
+// Genre represents a genre of ARIB STD-B10, which is the content_nibble_level_1 value.
+type Genre int
+
+// The genres of ARIB STD-B10.
+const (
+	GenreNews        Genre = 0x0
+	GenreSports      Genre = 0x1
+	GenreInformation Genre = 0x2
+	GenreDrama       Genre = 0x3
+	GenreMusic       Genre = 0x4
+	GenreVariety     Genre = 0x5
+	GenreMovie       Genre = 0x6
+	GenreAnime       Genre = 0x7
+	GenreDocumentary Genre = 0x8
+	GenreTheater     Genre = 0x9
+	GenreHobby       Genre = 0xA
+	GenreWelfare     Genre = 0xB
+	GenreExtension   Genre = 0xE
+	GenreOther       Genre = 0xF
+)
+
+var genreNames = map[Genre][2]string{
+	GenreNews:        {"ニュース／報道", "News/Report"},
+	GenreSports:      {"スポーツ", "Sports"},
+	GenreInformation: {"情報／ワイドショー", "Information/Tabloid Show"},
+	GenreDrama:       {"ドラマ", "Drama"},
+	GenreMusic:       {"音楽", "Music"},
+	GenreVariety:     {"バラエティ", "Variety"},
+	GenreMovie:       {"映画", "Movie"},
+	GenreAnime:       {"アニメ／特撮", "Anime/Tokusatsu"},
+	GenreDocumentary: {"ドキュメンタリー／教養", "Documentary/Culture"},
+	GenreTheater:     {"劇場／公演", "Theater/Performance"},
+	GenreHobby:       {"趣味／教育", "Hobby/Education"},
+	GenreWelfare:     {"福祉", "Welfare"},
+	GenreExtension:   {"拡張", "Extension"},
+	GenreOther:       {"その他", "Other"},
+}
+
+// String returns the English name of the genre.
+func (g Genre) String() string {
+	if names, ok := genreNames[g]; ok {
+		return names[1]
+	}
+
+	return fmt.Sprintf("Genre(0x%X)", int(g))
+}
+
+// Japanese returns the Japanese name of the genre.
+func (g Genre) Japanese() string {
+	if names, ok := genreNames[g]; ok {
+		return names[0]
+	}
+
+	return fmt.Sprintf("Genre(0x%X)", int(g))
+}
+
+// SubGenre represents a sub-genre of ARIB STD-B10, which combines the
+// content_nibble_level_1 value in the high nibble and the content_nibble_level_2
+// value in the low nibble.
+type SubGenre int
+
+// The sub-genres of ARIB STD-B10.
+const (
+	SubGenreNewsRegular       SubGenre = 0x00
+	SubGenreNewsWeather       SubGenre = 0x01
+	SubGenreNewsFeature       SubGenre = 0x02
+	SubGenreNewsPolitics      SubGenre = 0x03
+	SubGenreNewsEconomy       SubGenre = 0x04
+	SubGenreNewsInternational SubGenre = 0x05
+	SubGenreNewsCommentary    SubGenre = 0x06
+	SubGenreNewsDiscussion    SubGenre = 0x07
+	SubGenreNewsSpecialReport SubGenre = 0x08
+	SubGenreNewsLocal         SubGenre = 0x09
+	SubGenreNewsTraffic       SubGenre = 0x0A
+	SubGenreNewsOther         SubGenre = 0x0F
+
+	SubGenreSportsNews         SubGenre = 0x10
+	SubGenreSportsBaseball     SubGenre = 0x11
+	SubGenreSportsSoccer       SubGenre = 0x12
+	SubGenreSportsGolf         SubGenre = 0x13
+	SubGenreSportsBallGames    SubGenre = 0x14
+	SubGenreSportsMartialArts  SubGenre = 0x15
+	SubGenreSportsOlympics     SubGenre = 0x16
+	SubGenreSportsAthletics    SubGenre = 0x17
+	SubGenreSportsMotorSports  SubGenre = 0x18
+	SubGenreSportsMarineWinter SubGenre = 0x19
+	SubGenreSportsRacing       SubGenre = 0x1A
+	SubGenreSportsOther        SubGenre = 0x1F
+
+	SubGenreInformationEntertainment SubGenre = 0x20
+	SubGenreInformationFashion       SubGenre = 0x21
+	SubGenreInformationLiving        SubGenre = 0x22
+	SubGenreInformationHealth        SubGenre = 0x23
+	SubGenreInformationShopping      SubGenre = 0x24
+	SubGenreInformationGourmet       SubGenre = 0x25
+	SubGenreInformationEvents        SubGenre = 0x26
+	SubGenreInformationProgramGuide  SubGenre = 0x27
+	SubGenreInformationOther         SubGenre = 0x2F
+
+	SubGenreDramaDomestic SubGenre = 0x30
+	SubGenreDramaForeign  SubGenre = 0x31
+	SubGenreDramaPeriod   SubGenre = 0x32
+	SubGenreDramaOther    SubGenre = 0x3F
+
+	SubGenreMusicDomesticPop SubGenre = 0x40
+	SubGenreMusicForeignPop  SubGenre = 0x41
+	SubGenreMusicClassical   SubGenre = 0x42
+	SubGenreMusicJazz        SubGenre = 0x43
+	SubGenreMusicKayokyoku   SubGenre = 0x44
+	SubGenreMusicLive        SubGenre = 0x45
+	SubGenreMusicRanking     SubGenre = 0x46
+	SubGenreMusicKaraoke     SubGenre = 0x47
+	SubGenreMusicTraditional SubGenre = 0x48
+	SubGenreMusicChildren    SubGenre = 0x49
+	SubGenreMusicWorld       SubGenre = 0x4A
+	SubGenreMusicOther       SubGenre = 0x4F
+
+	SubGenreVarietyQuiz    SubGenre = 0x50
+	SubGenreVarietyGame    SubGenre = 0x51
+	SubGenreVarietyTalk    SubGenre = 0x52
+	SubGenreVarietyComedy  SubGenre = 0x53
+	SubGenreVarietyMusic   SubGenre = 0x54
+	SubGenreVarietyTravel  SubGenre = 0x55
+	SubGenreVarietyCooking SubGenre = 0x56
+	SubGenreVarietyOther   SubGenre = 0x5F
+
+	SubGenreMovieForeign  SubGenre = 0x60
+	SubGenreMovieDomestic SubGenre = 0x61
+	SubGenreMovieAnime    SubGenre = 0x62
+	SubGenreMovieOther    SubGenre = 0x6F
+
+	SubGenreAnimeDomestic  SubGenre = 0x70
+	SubGenreAnimeForeign   SubGenre = 0x71
+	SubGenreAnimeTokusatsu SubGenre = 0x72
+	SubGenreAnimeOther     SubGenre = 0x7F
+
+	SubGenreDocumentarySociety    SubGenre = 0x80
+	SubGenreDocumentaryHistory    SubGenre = 0x81
+	SubGenreDocumentaryNature     SubGenre = 0x82
+	SubGenreDocumentaryScience    SubGenre = 0x83
+	SubGenreDocumentaryCulture    SubGenre = 0x84
+	SubGenreDocumentaryLiterature SubGenre = 0x85
+	SubGenreDocumentarySports     SubGenre = 0x86
+	SubGenreDocumentaryGeneral    SubGenre = 0x87
+	SubGenreDocumentaryInterview  SubGenre = 0x88
+	SubGenreDocumentaryOther      SubGenre = 0x8F
+
+	SubGenreTheaterModern  SubGenre = 0x90
+	SubGenreTheaterMusical SubGenre = 0x91
+	SubGenreTheaterDance   SubGenre = 0x92
+	SubGenreTheaterRakugo  SubGenre = 0x93
+	SubGenreTheaterKabuki  SubGenre = 0x94
+	SubGenreTheaterOther   SubGenre = 0x9F
+
+	SubGenreHobbyOutdoor          SubGenre = 0xA0
+	SubGenreHobbyGardening        SubGenre = 0xA1
+	SubGenreHobbyArts             SubGenre = 0xA2
+	SubGenreHobbyGoShogi          SubGenre = 0xA3
+	SubGenreHobbyMahjong          SubGenre = 0xA4
+	SubGenreHobbyCars             SubGenre = 0xA5
+	SubGenreHobbyComputer         SubGenre = 0xA6
+	SubGenreHobbyLanguages        SubGenre = 0xA7
+	SubGenreHobbyChildren         SubGenre = 0xA8
+	SubGenreHobbyStudents         SubGenre = 0xA9
+	SubGenreHobbyUniversity       SubGenre = 0xAA
+	SubGenreHobbyLifelongLearning SubGenre = 0xAB
+	SubGenreHobbyEducationIssues  SubGenre = 0xAC
+	SubGenreHobbyOther            SubGenre = 0xAF
+
+	SubGenreWelfareElderly      SubGenre = 0xB0
+	SubGenreWelfareDisabled     SubGenre = 0xB1
+	SubGenreWelfareSocial       SubGenre = 0xB2
+	SubGenreWelfareVolunteer    SubGenre = 0xB3
+	SubGenreWelfareSignLanguage SubGenre = 0xB4
+	SubGenreWelfareCaptions     SubGenre = 0xB5
+	SubGenreWelfareAudioDesc    SubGenre = 0xB6
+	SubGenreWelfareOther        SubGenre = 0xBF
+
+	SubGenreExtensionBroadcast    SubGenre = 0xE0
+	SubGenreExtensionWidebandCS   SubGenre = 0xE1
+	SubGenreExtensionDigitalAudio SubGenre = 0xE2
+	SubGenreExtensionServer       SubGenre = 0xE3
+	SubGenreExtensionIP           SubGenre = 0xE4
+	SubGenreOther                 SubGenre = 0xFF
+)
+
+var subGenreNames = map[SubGenre][2]string{
+	SubGenreNewsRegular:       {"定時・総合", "Regular/General"},
+	SubGenreNewsWeather:       {"天気", "Weather"},
+	SubGenreNewsFeature:       {"特集・ドキュメント", "Feature/Document"},
+	SubGenreNewsPolitics:      {"政治・国会", "Politics/Diet"},
+	SubGenreNewsEconomy:       {"経済・市況", "Economy/Market"},
+	SubGenreNewsInternational: {"海外・国際", "Overseas/International"},
+	SubGenreNewsCommentary:    {"解説", "Commentary"},
+	SubGenreNewsDiscussion:    {"討論・会談", "Discussion/Conference"},
+	SubGenreNewsSpecialReport: {"報道特番", "Special Report"},
+	SubGenreNewsLocal:         {"ローカル・地域", "Local/Regional"},
+	SubGenreNewsTraffic:       {"交通", "Traffic"},
+	SubGenreNewsOther:         {"その他", "Other"},
+
+	SubGenreSportsNews:         {"スポーツニュース", "Sports News"},
+	SubGenreSportsBaseball:     {"野球", "Baseball"},
+	SubGenreSportsSoccer:       {"サッカー", "Soccer"},
+	SubGenreSportsGolf:         {"ゴルフ", "Golf"},
+	SubGenreSportsBallGames:    {"その他の球技", "Other Ball Games"},
+	SubGenreSportsMartialArts:  {"相撲・格闘技", "Sumo/Martial Arts"},
+	SubGenreSportsOlympics:     {"オリンピック・国際大会", "Olympics/International Games"},
+	SubGenreSportsAthletics:    {"マラソン・陸上・水泳", "Marathon/Athletics/Swimming"},
+	SubGenreSportsMotorSports:  {"モータースポーツ", "Motor Sports"},
+	SubGenreSportsMarineWinter: {"マリン・ウィンタースポーツ", "Marine/Winter Sports"},
+	SubGenreSportsRacing:       {"競馬・公営競技", "Horse Racing/Public Races"},
+	SubGenreSportsOther:        {"その他", "Other"},
+
+	SubGenreInformationEntertainment: {"芸能・ワイドショー", "Entertainment/Tabloid Show"},
+	SubGenreInformationFashion:       {"ファッション", "Fashion"},
+	SubGenreInformationLiving:        {"暮らし・住まい", "Living/Housing"},
+	SubGenreInformationHealth:        {"健康・医療", "Health/Medical"},
+	SubGenreInformationShopping:      {"ショッピング・通販", "Shopping/Mail Order"},
+	SubGenreInformationGourmet:       {"グルメ・料理", "Gourmet/Cooking"},
+	SubGenreInformationEvents:        {"イベント", "Events"},
+	SubGenreInformationProgramGuide:  {"番組紹介・お知らせ", "Program Guide/Announcements"},
+	SubGenreInformationOther:         {"その他", "Other"},
+
+	SubGenreDramaDomestic: {"国内ドラマ", "Domestic Drama"},
+	SubGenreDramaForeign:  {"海外ドラマ", "Foreign Drama"},
+	SubGenreDramaPeriod:   {"時代劇", "Period Drama"},
+	SubGenreDramaOther:    {"その他", "Other"},
+
+	SubGenreMusicDomesticPop: {"国内ロック・ポップス", "Domestic Rock/Pop"},
+	SubGenreMusicForeignPop:  {"海外ロック・ポップス", "Foreign Rock/Pop"},
+	SubGenreMusicClassical:   {"クラシック・オペラ", "Classical/Opera"},
+	SubGenreMusicJazz:        {"ジャズ・フュージョン", "Jazz/Fusion"},
+	SubGenreMusicKayokyoku:   {"歌謡曲・演歌", "Kayokyoku/Enka"},
+	SubGenreMusicLive:        {"ライブ・コンサート", "Live/Concert"},
+	SubGenreMusicRanking:     {"ランキング・リクエスト", "Ranking/Request"},
+	SubGenreMusicKaraoke:     {"カラオケ・のど自慢", "Karaoke/Singing Contest"},
+	SubGenreMusicTraditional: {"民謡・邦楽", "Folk/Japanese Traditional"},
+	SubGenreMusicChildren:    {"童謡・キッズ", "Nursery Rhymes/Kids"},
+	SubGenreMusicWorld:       {"民族音楽・ワールドミュージック", "Ethnic/World Music"},
+	SubGenreMusicOther:       {"その他", "Other"},
+
+	SubGenreVarietyQuiz:    {"クイズ", "Quiz"},
+	SubGenreVarietyGame:    {"ゲーム", "Game"},
+	SubGenreVarietyTalk:    {"トークバラエティ", "Talk Variety"},
+	SubGenreVarietyComedy:  {"お笑い・コメディ", "Comedy"},
+	SubGenreVarietyMusic:   {"音楽バラエティ", "Music Variety"},
+	SubGenreVarietyTravel:  {"旅バラエティ", "Travel Variety"},
+	SubGenreVarietyCooking: {"料理バラエティ", "Cooking Variety"},
+	SubGenreVarietyOther:   {"その他", "Other"},
+
+	SubGenreMovieForeign:  {"洋画", "Foreign Movie"},
+	SubGenreMovieDomestic: {"邦画", "Domestic Movie"},
+	SubGenreMovieAnime:    {"アニメ", "Anime"},
+	SubGenreMovieOther:    {"その他", "Other"},
+
+	SubGenreAnimeDomestic:  {"国内アニメ", "Domestic Anime"},
+	SubGenreAnimeForeign:   {"海外アニメ", "Foreign Anime"},
+	SubGenreAnimeTokusatsu: {"特撮", "Tokusatsu"},
+	SubGenreAnimeOther:     {"その他", "Other"},
+
+	SubGenreDocumentarySociety:    {"社会・時事", "Society/Current Affairs"},
+	SubGenreDocumentaryHistory:    {"歴史・紀行", "History/Travelogue"},
+	SubGenreDocumentaryNature:     {"自然・動物・環境", "Nature/Animals/Environment"},
+	SubGenreDocumentaryScience:    {"宇宙・科学・医学", "Space/Science/Medicine"},
+	SubGenreDocumentaryCulture:    {"カルチャー・伝統文化", "Culture/Traditional Culture"},
+	SubGenreDocumentaryLiterature: {"文学・文芸", "Literature"},
+	SubGenreDocumentarySports:     {"スポーツ", "Sports"},
+	SubGenreDocumentaryGeneral:    {"ドキュメンタリー全般", "General Documentary"},
+	SubGenreDocumentaryInterview:  {"インタビュー・討論", "Interview/Discussion"},
+	SubGenreDocumentaryOther:      {"その他", "Other"},
+
+	SubGenreTheaterModern:  {"現代劇・新劇", "Modern Drama"},
+	SubGenreTheaterMusical: {"ミュージカル", "Musical"},
+	SubGenreTheaterDance:   {"ダンス・バレエ", "Dance/Ballet"},
+	SubGenreTheaterRakugo:  {"落語・演芸", "Rakugo/Entertainment"},
+	SubGenreTheaterKabuki:  {"歌舞伎・古典", "Kabuki/Classical"},
+	SubGenreTheaterOther:   {"その他", "Other"},
+
+	SubGenreHobbyOutdoor:          {"旅・釣り・アウトドア", "Travel/Fishing/Outdoor"},
+	SubGenreHobbyGardening:        {"園芸・ペット・手芸", "Gardening/Pets/Handicrafts"},
+	SubGenreHobbyArts:             {"音楽・美術・工芸", "Music/Art/Crafts"},
+	SubGenreHobbyGoShogi:          {"囲碁・将棋", "Go/Shogi"},
+	SubGenreHobbyMahjong:          {"麻雀・パチンコ", "Mahjong/Pachinko"},
+	SubGenreHobbyCars:             {"車・オートバイ", "Cars/Motorcycles"},
+	SubGenreHobbyComputer:         {"コンピュータ・ＴＶゲーム", "Computers/Video Games"},
+	SubGenreHobbyLanguages:        {"会話・語学", "Conversation/Languages"},
+	SubGenreHobbyChildren:         {"幼児・小学生", "Preschool/Elementary School"},
+	SubGenreHobbyStudents:         {"中学生・高校生", "Junior High/High School"},
+	SubGenreHobbyUniversity:       {"大学生・受験", "University/Entrance Exams"},
+	SubGenreHobbyLifelongLearning: {"生涯教育・資格", "Lifelong Learning/Qualifications"},
+	SubGenreHobbyEducationIssues:  {"教育問題", "Educational Issues"},
+	SubGenreHobbyOther:            {"その他", "Other"},
+
+	SubGenreWelfareElderly:      {"高齢者", "Elderly"},
+	SubGenreWelfareDisabled:     {"障害者", "Disabled"},
+	SubGenreWelfareSocial:       {"社会福祉", "Social Welfare"},
+	SubGenreWelfareVolunteer:    {"ボランティア", "Volunteer"},
+	SubGenreWelfareSignLanguage: {"手話", "Sign Language"},
+	SubGenreWelfareCaptions:     {"文字（字幕）", "Captions"},
+	SubGenreWelfareAudioDesc:    {"音声解説", "Audio Description"},
+	SubGenreWelfareOther:        {"その他", "Other"},
+
+	SubGenreExtensionBroadcast:    {"BS/地上デジタル放送用番組付属情報", "BS/Terrestrial Program Attributes"},
+	SubGenreExtensionWidebandCS:   {"広帯域CSデジタル放送用拡張", "Wideband CS Extension"},
+	SubGenreExtensionDigitalAudio: {"衛星デジタル音声放送用拡張", "Satellite Digital Audio Extension"},
+	SubGenreExtensionServer:       {"サーバー型番組付属情報", "Server-Type Program Attributes"},
+	SubGenreExtensionIP:           {"IP放送用番組付属情報", "IP Broadcast Program Attributes"},
+
+	SubGenreOther: {"その他", "Other"},
+}
+
+// Genre returns the genre which the sub-genre belongs to.
+func (s SubGenre) Genre() Genre {
+	return Genre(s >> 4)
+}
+
+// String returns the English name of the sub-genre.
+func (s SubGenre) String() string {
+	if names, ok := subGenreNames[s]; ok {
+		return names[1]
+	}
+
+	return fmt.Sprintf("SubGenre(0x%02X)", int(s))
+}
+
+// Japanese returns the Japanese name of the sub-genre.
+func (s SubGenre) Japanese() string {
+	if names, ok := subGenreNames[s]; ok {
+		return names[0]
+	}
+
+	return fmt.Sprintf("SubGenre(0x%02X)", int(s))
+}
+
+// ExtendedGenre represents a genre extended by the user nibbles for the wideband
+// CS digital broadcasting, which combines the user_nibble values as SubGenre does.
+type ExtendedGenre int
+
+// The extended genres for the wideband CS digital broadcasting.
+const (
+	ExtendedGenreSportsTennis           ExtendedGenre = 0x00
+	ExtendedGenreSportsBasketball       ExtendedGenre = 0x01
+	ExtendedGenreSportsRugby            ExtendedGenre = 0x02
+	ExtendedGenreSportsAmericanFootball ExtendedGenre = 0x03
+	ExtendedGenreSportsBoxing           ExtendedGenre = 0x04
+	ExtendedGenreSportsProWrestling     ExtendedGenre = 0x05
+	ExtendedGenreSportsOther            ExtendedGenre = 0x0F
+
+	ExtendedGenreForeignMovieAction      ExtendedGenre = 0x10
+	ExtendedGenreForeignMovieSciFi       ExtendedGenre = 0x11
+	ExtendedGenreForeignMovieComedy      ExtendedGenre = 0x12
+	ExtendedGenreForeignMovieSuspense    ExtendedGenre = 0x13
+	ExtendedGenreForeignMovieRomance     ExtendedGenre = 0x14
+	ExtendedGenreForeignMovieHorror      ExtendedGenre = 0x15
+	ExtendedGenreForeignMovieWestern     ExtendedGenre = 0x16
+	ExtendedGenreForeignMovieDrama       ExtendedGenre = 0x17
+	ExtendedGenreForeignMovieAnimation   ExtendedGenre = 0x18
+	ExtendedGenreForeignMovieDocumentary ExtendedGenre = 0x19
+	ExtendedGenreForeignMovieAdventure   ExtendedGenre = 0x1A
+	ExtendedGenreForeignMovieMusical     ExtendedGenre = 0x1B
+	ExtendedGenreForeignMovieHomeDrama   ExtendedGenre = 0x1C
+	ExtendedGenreForeignMovieOther       ExtendedGenre = 0x1F
+
+	ExtendedGenreDomesticMovieAction      ExtendedGenre = 0x20
+	ExtendedGenreDomesticMovieSciFi       ExtendedGenre = 0x21
+	ExtendedGenreDomesticMovieComedy      ExtendedGenre = 0x22
+	ExtendedGenreDomesticMovieSuspense    ExtendedGenre = 0x23
+	ExtendedGenreDomesticMovieRomance     ExtendedGenre = 0x24
+	ExtendedGenreDomesticMovieHorror      ExtendedGenre = 0x25
+	ExtendedGenreDomesticMovieYouth       ExtendedGenre = 0x26
+	ExtendedGenreDomesticMovieYakuza      ExtendedGenre = 0x27
+	ExtendedGenreDomesticMovieAnimation   ExtendedGenre = 0x28
+	ExtendedGenreDomesticMovieDocumentary ExtendedGenre = 0x29
+	ExtendedGenreDomesticMovieAdventure   ExtendedGenre = 0x2A
+	ExtendedGenreDomesticMovieMusical     ExtendedGenre = 0x2B
+	ExtendedGenreDomesticMovieHomeDrama   ExtendedGenre = 0x2C
+	ExtendedGenreDomesticMovieOther       ExtendedGenre = 0x2F
+)
+
+var extendedGenreNames = map[ExtendedGenre][2]string{
+	ExtendedGenreSportsTennis:           {"テニス", "Tennis"},
+	ExtendedGenreSportsBasketball:       {"バスケットボール", "Basketball"},
+	ExtendedGenreSportsRugby:            {"ラグビー", "Rugby"},
+	ExtendedGenreSportsAmericanFootball: {"アメリカンフットボール", "American Football"},
+	ExtendedGenreSportsBoxing:           {"ボクシング", "Boxing"},
+	ExtendedGenreSportsProWrestling:     {"プロレス", "Pro Wrestling"},
+	ExtendedGenreSportsOther:            {"その他", "Other"},
+
+	ExtendedGenreForeignMovieAction:      {"アクション", "Action"},
+	ExtendedGenreForeignMovieSciFi:       {"SF／ファンタジー", "Sci-Fi/Fantasy"},
+	ExtendedGenreForeignMovieComedy:      {"コメディー", "Comedy"},
+	ExtendedGenreForeignMovieSuspense:    {"サスペンス／ミステリー", "Suspense/Mystery"},
+	ExtendedGenreForeignMovieRomance:     {"恋愛／ロマンス", "Romance"},
+	ExtendedGenreForeignMovieHorror:      {"ホラー／スリラー", "Horror/Thriller"},
+	ExtendedGenreForeignMovieWestern:     {"ウエスタン", "Western"},
+	ExtendedGenreForeignMovieDrama:       {"ドラマ／社会派ドラマ", "Drama/Social Drama"},
+	ExtendedGenreForeignMovieAnimation:   {"アニメーション", "Animation"},
+	ExtendedGenreForeignMovieDocumentary: {"ドキュメンタリー", "Documentary"},
+	ExtendedGenreForeignMovieAdventure:   {"アドベンチャー／冒険", "Adventure"},
+	ExtendedGenreForeignMovieMusical:     {"ミュージカル／音楽映画", "Musical/Music Film"},
+	ExtendedGenreForeignMovieHomeDrama:   {"ホームドラマ", "Home Drama"},
+	ExtendedGenreForeignMovieOther:       {"その他", "Other"},
+
+	ExtendedGenreDomesticMovieAction:      {"アクション", "Action"},
+	ExtendedGenreDomesticMovieSciFi:       {"SF／ファンタジー", "Sci-Fi/Fantasy"},
+	ExtendedGenreDomesticMovieComedy:      {"お笑い／コメディー", "Comedy"},
+	ExtendedGenreDomesticMovieSuspense:    {"サスペンス／ミステリー", "Suspense/Mystery"},
+	ExtendedGenreDomesticMovieRomance:     {"恋愛／ロマンス", "Romance"},
+	ExtendedGenreDomesticMovieHorror:      {"ホラー／スリラー", "Horror/Thriller"},
+	ExtendedGenreDomesticMovieYouth:       {"青春／学園／アイドル", "Youth/School/Idol"},
+	ExtendedGenreDomesticMovieYakuza:      {"任侠／時代劇", "Yakuza/Period Drama"},
+	ExtendedGenreDomesticMovieAnimation:   {"アニメーション", "Animation"},
+	ExtendedGenreDomesticMovieDocumentary: {"ドキュメンタリー", "Documentary"},
+	ExtendedGenreDomesticMovieAdventure:   {"アドベンチャー／冒険", "Adventure"},
+	ExtendedGenreDomesticMovieMusical:     {"ミュージカル／音楽映画", "Musical/Music Film"},
+	ExtendedGenreDomesticMovieHomeDrama:   {"ホームドラマ", "Home Drama"},
+	ExtendedGenreDomesticMovieOther:       {"その他", "Other"},
+}
+
+// Genre returns the genre which the extended genre belongs to.
+func (e ExtendedGenre) Genre() Genre {
+	if e>>4 == 0x0 {
+		return GenreSports
+	}
+
+	return GenreMovie
+}
+
+// String returns the English name of the extended genre.
+func (e ExtendedGenre) String() string {
+	if names, ok := extendedGenreNames[e]; ok {
+		return names[1]
+	}
+
+	return fmt.Sprintf("ExtendedGenre(0x%02X)", int(e))
+}
+
+// Japanese returns the Japanese name of the extended genre.
+func (e ExtendedGenre) Japanese() string {
+	if names, ok := extendedGenreNames[e]; ok {
+		return names[0]
+	}
+
+	return fmt.Sprintf("ExtendedGenre(0x%02X)", int(e))
+}
+
+// Genre returns the genre of the content_nibble_level_1 value.
+func (g ProgramGenre) Genre() Genre {
+	return Genre(g.Level1)
+}
+
+// SubGenre returns the sub-genre of the content_nibble values.
+func (g ProgramGenre) SubGenre() SubGenre {
+	return SubGenre(g.Level1<<4 | g.Level2)
+}
+
+// ExtendedGenre returns the extended genre of the user_nibble values,
+// which is available only for the wideband CS digital broadcasting extension.
+func (g ProgramGenre) ExtendedGenre() (ExtendedGenre, bool) {
+	if g.SubGenre() != SubGenreExtensionWidebandCS || g.UserNibble1 > 0x2 {
+		return 0, false
+	}
+
+	return ExtendedGenre(g.UserNibble1<<4 | g.UserNibble2), true
+}
+
+// HasGenre reports whether the program has any of the genre g,
+// including the genres extended for the wideband CS digital broadcasting.
+func (p *Program) HasGenre(g Genre) bool {
+	for _, genre := range p.Genres {
+		if genre.Genre() == g {
+			return true
+		}
+
+		if e, ok := genre.ExtendedGenre(); ok && e.Genre() == g {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MajorGenres returns the distinct genres of the program in order, in which
+// the genres extended for the wideband CS digital broadcasting are resolved.
+func (p *Program) MajorGenres() []Genre {
+	var genres []Genre
+	seen := map[Genre]bool{}
+	for _, genre := range p.Genres {
+		g := genre.Genre()
+		if e, ok := genre.ExtendedGenre(); ok {
+			g = e.Genre()
+		}
+
+		if g == GenreExtension || seen[g] {
+			continue
+		}
+		seen[g] = true
+
+		genres = append(genres, g)
+	}
+
+	return genres
+}
+
+// HasSubGenre reports whether the program has the sub-genre s.
+func (p *Program) HasSubGenre(s SubGenre) bool {
+	for _, genre := range p.Genres {
+		if genre.SubGenre() == s {
+			return true
+		}
+	}
+
+	return false
+}
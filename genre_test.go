@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestProgramGenre(t *testing.T) {
+	data, err := os.ReadFile("testdata/program.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program := new(Program)
+	if err := json.Unmarshal(data, program); err != nil {
+		t.Fatal(err)
+	}
+
+	genre := program.Genres[0]
+
+	if got, want := genre.Genre(), GenreAnime; got != want {
+		t.Errorf("genre is %v, want %v", got, want)
+	}
+
+	if got, want := genre.SubGenre(), SubGenreAnimeDomestic; got != want {
+		t.Errorf("sub-genre is %v, want %v", got, want)
+	}
+
+	if _, ok := genre.ExtendedGenre(); ok {
+		t.Error("genre should not have extended genre")
+	}
+
+	if !program.HasGenre(GenreAnime) {
+		t.Error("program should have anime genre")
+	}
+
+	if program.HasGenre(GenreMovie) {
+		t.Error("program should not have movie genre")
+	}
+
+	if !program.HasSubGenre(SubGenreAnimeDomestic) {
+		t.Error("program should have domestic anime sub-genre")
+	}
+}
+
+func TestProgramGenre_ExtendedGenre(t *testing.T) {
+	program := &Program{Genres: []ProgramGenre{{Level1: 0xE, Level2: 0x1, UserNibble1: 0x1, UserNibble2: 0x1}}}
+
+	e, ok := program.Genres[0].ExtendedGenre()
+	if !ok {
+		t.Fatal("genre should have extended genre")
+	}
+
+	if got, want := e, ExtendedGenreForeignMovieSciFi; got != want {
+		t.Errorf("extended genre is %v, want %v", got, want)
+	}
+
+	if !program.HasGenre(GenreMovie) {
+		t.Error("program should have movie genre")
+	}
+
+	if !program.HasGenre(GenreExtension) {
+		t.Error("program should have extension genre")
+	}
+}
+
+func TestProgram_MajorGenres(t *testing.T) {
+	program := &Program{Genres: []ProgramGenre{
+		{Level1: 0x7, Level2: 0x0},
+		{Level1: 0xE, Level2: 0x1, UserNibble1: 0x1, UserNibble2: 0x1},
+		{Level1: 0x7, Level2: 0x1},
+		{Level1: 0xE, Level2: 0x0},
+	}}
+
+	genres := program.MajorGenres()
+	if got, want := len(genres), 2; got != want {
+		t.Fatalf("genres are %v, want %v genres", genres, want)
+	}
+
+	if got, want := genres[0], GenreAnime; got != want {
+		t.Errorf("first genre is %v, want %v", got, want)
+	}
+
+	if got, want := genres[1], GenreMovie; got != want {
+		t.Errorf("second genre is %v, want %v", got, want)
+	}
+}
+
+func TestGenre_String(t *testing.T) {
+	tests := []struct {
+		s        interface{ String() string }
+		english  string
+		japanese string
+	}{
+		{GenreNews, "News/Report", "ニュース／報道"},
+		{GenreAnime, "Anime/Tokusatsu", "アニメ／特撮"},
+		{Genre(0xC), "Genre(0xC)", "Genre(0xC)"},
+		{SubGenreSportsBaseball, "Baseball", "野球"},
+		{SubGenreWelfareCaptions, "Captions", "文字（字幕）"},
+		{SubGenre(0x0B), "SubGenre(0x0B)", "SubGenre(0x0B)"},
+		{ExtendedGenreDomesticMovieYakuza, "Yakuza/Period Drama", "任侠／時代劇"},
+	}
+
+	for _, test := range tests {
+		if got, want := test.s.String(), test.english; got != want {
+			t.Errorf("English name is %v, want %v", got, want)
+		}
+
+		japanese := test.s.(interface{ Japanese() string }).Japanese()
+		if got, want := japanese, test.japanese; got != want {
+			t.Errorf("Japanese name is %v, want %v", got, want)
+		}
+	}
+
+	if got, want := SubGenreWelfareCaptions.Genre(), GenreWelfare; got != want {
+		t.Errorf("genre of %v is %v, want %v", SubGenreWelfareCaptions, got, want)
+	}
+}
@@ -0,0 +1,8 @@
+module ykzts.com/x/mirakurun
+
+go 1.18
+
+require (
+	github.com/google/go-querystring v1.1.0
+	golang.org/x/text v0.14.0
+)
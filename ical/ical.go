@@ -0,0 +1,238 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+/*
+Package ical writes Mirakurun programs as an iCalendar (RFC 5545) document.
+
+The UID of each event is derived from the program ID, which Mirakurun keeps
+when a program is rescheduled, so calendar applications update the event
+instead of adding a new one.
+*/
+package ical // import "ykzts.com/x/mirakurun/ical"
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ykzts.com/x/mirakurun"
+)
+
+const (
+	timeLayout    = "20060102T150405"
+	utcTimeLayout = "20060102T150405Z"
+	timeZoneID    = "Asia/Tokyo"
+	maxLineOctets = 75
+)
+
+// UID returns the UID of the event for the program with the Mirakurun program ID id.
+func UID(id int) string {
+	return fmt.Sprintf("%d@mirakurun", id)
+}
+
+// A Writer writes an iCalendar document.
+//
+// Close must be called to finish the document.
+type Writer struct {
+	// Name is the name of the calendar written as the X-WR-CALNAME property.
+	Name string
+
+	// Now returns the time written as the DTSTAMP property. If nil, time.Now is used.
+	Now func() time.Time
+
+	w       *bufio.Writer
+	started bool
+	closed  bool
+}
+
+// NewWriter returns a new Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+func (w *Writer) start() error {
+	if w.closed {
+		return errors.New("ical: write to closed Writer")
+	}
+
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//ykzts.com//go-mirakurun//JA")
+	w.line("CALSCALE:GREGORIAN")
+	if w.Name != "" {
+		w.property("X-WR-CALNAME", w.Name)
+	}
+	w.line("X-WR-TIMEZONE:" + timeZoneID)
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + timeZoneID)
+	w.line("BEGIN:STANDARD")
+	w.line("DTSTART:19700101T000000")
+	w.line("TZOFFSETFROM:+0900")
+	w.line("TZOFFSETTO:+0900")
+	w.line("TZNAME:JST")
+	w.line("END:STANDARD")
+	w.line("END:VTIMEZONE")
+
+	return nil
+}
+
+// WriteEvent writes an event for the program, whose location is the name of the service.
+func (w *Writer) WriteEvent(program *mirakurun.Program, location string) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	now := time.Now
+	if w.Now != nil {
+		now = w.Now
+	}
+
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + UID(program.ID))
+	w.line("DTSTAMP:" + now().UTC().Format(utcTimeLayout))
+	w.line("DTSTART;TZID=" + timeZoneID + ":" + program.StartAt.JST().Format(timeLayout))
+	w.line("DTEND;TZID=" + timeZoneID + ":" + program.EndAt().In(mirakurun.JST).Format(timeLayout))
+	w.property("SUMMARY", program.Name)
+	if desc := program.FullDescription(); desc != "" {
+		w.property("DESCRIPTION", desc)
+	}
+	if location != "" {
+		w.property("LOCATION", location)
+	}
+	if categories := categories(program); len(categories) > 0 {
+		w.property("CATEGORIES", categories...)
+	}
+	w.line("END:VEVENT")
+
+	return nil
+}
+
+// Close finishes the document. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	w.line("END:VCALENDAR")
+
+	return w.w.Flush()
+}
+
+// property writes a property whose values are escaped as TEXT.
+func (w *Writer) property(name string, values ...string) {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = textEscaper.Replace(value)
+	}
+
+	w.line(name + ":" + strings.Join(escaped, ","))
+}
+
+// line writes a content line folded at 75 octets, including the leading space
+// of the continuation lines.
+func (w *Writer) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+
+		w.w.WriteString(s[:i])
+		w.w.WriteString("\r\n ")
+		s = s[i:]
+		limit = maxLineOctets - 1
+	}
+
+	w.w.WriteString(s)
+	w.w.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// Options specifies the optional parameters to the Write function.
+type Options struct {
+	// Name is the name of the calendar.
+	Name string
+
+	// Filter selects the programs to be written, such as SeriesID.
+	// If nil, all programs are written.
+	Filter func(*mirakurun.Program) bool
+
+	// Now returns the time written as the DTSTAMP property. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// Write writes an iCalendar document of the programs, such as the ones returned
+// by Client.GetPrograms, whose locations are the names of the services.
+func Write(w io.Writer, services []*mirakurun.Service, programs []*mirakurun.Program, opt *Options) error {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	names := make(map[int]string, len(services))
+	for _, service := range services {
+		names[service.ID] = service.Name
+	}
+
+	iw := NewWriter(w)
+	iw.Name = opt.Name
+	iw.Now = opt.Now
+
+	for _, program := range programs {
+		if opt.Filter != nil && !opt.Filter(program) {
+			continue
+		}
+
+		if err := iw.WriteEvent(program, names[program.ServiceItemID()]); err != nil {
+			return err
+		}
+	}
+
+	return iw.Close()
+}
+
+// SeriesID returns a filter selecting the programs of the series id.
+func SeriesID(id int) func(*mirakurun.Program) bool {
+	return func(p *mirakurun.Program) bool {
+		return p.Series.ID == id
+	}
+}
+
+func categories(program *mirakurun.Program) []string {
+	var names []string
+	for _, g := range program.MajorGenres() {
+		names = append(names, g.Japanese())
+	}
+
+	return names
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"ykzts.com/x/mirakurun"
+	"ykzts.com/x/mirakurun/internal/testutil"
+)
+
+func now() time.Time {
+	return time.Date(2018, 1, 20, 0, 0, 0, 0, time.UTC)
+}
+
+func TestWrite(t *testing.T) {
+	var services []*mirakurun.Service
+	testutil.ReadJSON(t, "../testdata/services.json", &services)
+
+	var programs []*mirakurun.Program
+	testutil.ReadJSON(t, "../testdata/epg_programs.json", &programs)
+
+	buf := new(bytes.Buffer)
+	opt := &Options{Name: "カードキャプターさくら", Filter: SeriesID(1234), Now: now}
+	if err := Write(buf, services, programs, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.CheckGolden(t, "testdata/series.golden.ics", buf.Bytes())
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line %q is longer than 75 octets", line)
+		}
+	}
+}
+
+func TestWriter_WriteEvent_rescheduled(t *testing.T) {
+	program := &mirakurun.Program{
+		ID:        40010310979,
+		NetworkID: 4,
+		ServiceID: 103,
+		StartAt:   mirakurun.NewTimestamp(time.Date(2018, 1, 21, 7, 30, 0, 0, mirakurun.JST)),
+		Duration:  mirakurun.Duration{Duration: 25 * time.Minute},
+		Name:      "カードキャプターさくら クリアカード編 第3話",
+	}
+
+	write := func() string {
+		buf := new(bytes.Buffer)
+		w := NewWriter(buf)
+		w.Now = now
+		if err := w.WriteEvent(program, "NHK BSプレミアム"); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		return buf.String()
+	}
+
+	before := write()
+	program.StartAt = mirakurun.NewTimestamp(program.StartAt.Add(15 * time.Minute))
+	after := write()
+
+	if !strings.Contains(before, "UID:40010310979@mirakurun\r\n") || !strings.Contains(after, "UID:40010310979@mirakurun\r\n") {
+		t.Errorf("UID should be kept, but\n%s\n%s", before, after)
+	}
+
+	if !strings.Contains(after, "DTSTART;TZID=Asia/Tokyo:20180121T074500\r\n") {
+		t.Errorf("DTSTART should be updated, but\n%s", after)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEntry represents a line of the Mirakurun log.
+type LogEntry struct {
+	Time    time.Time
+	Level   string
+	Message string
+
+	// Access is set if the line is an HTTP access log.
+	Access *AccessLog
+}
+
+// AccessLog represents an HTTP access log of Mirakurun, such as
+// "- - GET /api/services HTTP/1.1 200 - - 4.253 ms Chinachu/0.9.5".
+type AccessLog struct {
+	RemoteAddr    string
+	RemoteUser    string
+	Method        string
+	Path          string
+	Proto         string
+	Status        int
+	ContentLength int64 // -1 if unknown
+	Latency       time.Duration
+	UserAgent     string
+}
+
+var logLevels = map[string]bool{
+	"fatal":   true,
+	"error":   true,
+	"warning": true,
+	"info":    true,
+	"debug":   true,
+}
+
+// ParseLogEntry parses a line of the Mirakurun log, which may be prefixed with
+// the time and level, as in "2018-01-21T08:00:00.000+09:00 info: message".
+func ParseLogEntry(line string) *LogEntry {
+	entry := &LogEntry{Message: strings.TrimRight(line, "\r\n")}
+
+	if i := strings.IndexByte(entry.Message, ' '); i > 0 {
+		if t, err := time.Parse(time.RFC3339Nano, entry.Message[:i]); err == nil {
+			entry.Time = t
+			entry.Message = entry.Message[i+1:]
+		}
+	}
+
+	if i := strings.Index(entry.Message, ": "); i > 0 && logLevels[entry.Message[:i]] {
+		entry.Level = entry.Message[:i]
+		entry.Message = entry.Message[i+2:]
+	}
+
+	entry.Access = parseAccessLog(entry.Message)
+
+	return entry
+}
+
+func parseAccessLog(msg string) *AccessLog {
+	fields := strings.SplitN(msg, " ", 11)
+	if len(fields) < 10 || !strings.HasPrefix(fields[4], "HTTP/") || fields[9] != "ms" {
+		return nil
+	}
+
+	status, err := strconv.Atoi(fields[5])
+	if err != nil {
+		return nil
+	}
+
+	contentLength, err := strconv.ParseInt(fields[6], 10, 64)
+	if err != nil {
+		contentLength = -1
+	}
+
+	latency, err := strconv.ParseFloat(fields[8], 64)
+	if err != nil {
+		return nil
+	}
+
+	access := &AccessLog{
+		RemoteAddr:    fields[0],
+		RemoteUser:    fields[1],
+		Method:        fields[2],
+		Path:          fields[3],
+		Proto:         fields[4],
+		Status:        status,
+		ContentLength: contentLength,
+		Latency:       time.Duration(latency * float64(time.Millisecond)),
+	}
+	if len(fields) > 10 {
+		access.UserAgent = fields[10]
+	}
+
+	return access
+}
+
+// LogReader reads entries from a Mirakurun log.
+type LogReader struct {
+	ctx     context.Context
+	r       io.Reader
+	scanner *bufio.Scanner
+
+	entry *LogEntry
+	err   error
+}
+
+// NewLogReader returns a LogReader reading from r, such as the stream returned by
+// Client.GetLogStream.
+func NewLogReader(r io.Reader) *LogReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+
+	return &LogReader{ctx: context.Background(), r: r, scanner: scanner}
+}
+
+// SubscribeLog fetches a log stream and returns a LogReader reading it.
+// The stream ends when ctx is canceled.
+func (c *Client) SubscribeLog(ctx context.Context) (*LogReader, *http.Response, error) {
+	stream, resp, err := c.GetLogStream(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	r := NewLogReader(stream)
+	r.ctx = ctx
+
+	return r, resp, nil
+}
+
+// Next reads the next non-empty line, which will then be available through the Entry method.
+// It returns false when the log ends, either by reaching the end or an error.
+// After Next returns false, the Err method will return any error that occurred.
+func (r *LogReader) Next() bool {
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		r.entry = ParseLogEntry(line)
+		return true
+	}
+
+	r.entry = nil
+	if r.ctx.Err() != nil {
+		r.err = r.ctx.Err()
+	} else {
+		r.err = r.scanner.Err()
+	}
+
+	return false
+}
+
+// Entry returns the entry read by the last call to Next.
+func (r *LogReader) Entry() *LogEntry {
+	return r.entry
+}
+
+// Err returns the error that ended the log, or nil if the log reached the end.
+func (r *LogReader) Err() error {
+	return r.err
+}
+
+// Close closes the underlying reader if it is an io.Closer.
+func (r *LogReader) Close() error {
+	if closer, ok := r.r.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseLogEntry(t *testing.T) {
+	entry := ParseLogEntry("2018-01-21T08:00:00.153+09:00 info: 127.0.0.1 - GET /api/services/3239123608/stream?decode=1 HTTP/1.1 200 1024 - 12.5 ms recorder/1.0 go-mirakurun/1.0 Go/1.22.0")
+
+	if got, want := entry.Time.UnixNano(), time.Date(2018, 1, 20, 23, 0, 0, 153000000, time.UTC).UnixNano(); got != want {
+		t.Errorf("time is %v, want %v", got, want)
+	}
+
+	if got, want := entry.Level, "info"; got != want {
+		t.Errorf("level is %v, want %v", got, want)
+	}
+
+	if entry.Access == nil {
+		t.Fatal("access log is nil")
+	}
+
+	want := AccessLog{
+		RemoteAddr:    "127.0.0.1",
+		RemoteUser:    "-",
+		Method:        "GET",
+		Path:          "/api/services/3239123608/stream?decode=1",
+		Proto:         "HTTP/1.1",
+		Status:        200,
+		ContentLength: 1024,
+		Latency:       12500 * time.Microsecond,
+		UserAgent:     "recorder/1.0 go-mirakurun/1.0 Go/1.22.0",
+	}
+	if got := *entry.Access; got != want {
+		t.Errorf("access log is %+v, want %+v", got, want)
+	}
+
+	entry = ParseLogEntry("2018-01-21T08:00:00.153+09:00 warning: TunerDevice#0 process has closed with exit code=1 by signal `null` (pid=1234)")
+
+	if got, want := entry.Level, "warning"; got != want {
+		t.Errorf("level is %v, want %v", got, want)
+	}
+
+	if got, want := entry.Message, "TunerDevice#0 process has closed with exit code=1 by signal `null` (pid=1234)"; got != want {
+		t.Errorf("message is %v, want %v", got, want)
+	}
+
+	if entry.Access != nil {
+		t.Errorf("access log is %+v, want nil", entry.Access)
+	}
+}
+
+func TestNewLogReader(t *testing.T) {
+	file, err := os.Open("testdata/log.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewLogReader(file)
+	defer r.Close()
+
+	var entries []*LogEntry
+	for r.Next() {
+		entries = append(entries, r.Entry())
+	}
+
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(entries), 3; got != want {
+		t.Fatalf("entries length is %v, want %v", got, want)
+	}
+
+	access := entries[1].Access
+	if access == nil {
+		t.Fatal("access log is nil")
+	}
+
+	if got, want := access.Path, "/api/programs"; got != want {
+		t.Errorf("path is %v, want %v", got, want)
+	}
+
+	if got, want := access.ContentLength, int64(-1); got != want {
+		t.Errorf("content length is %v, want %v", got, want)
+	}
+
+	if got, want := access.Latency, 44643*time.Microsecond; got != want {
+		t.Errorf("latency is %v, want %v", got, want)
+	}
+
+	if got, want := access.UserAgent, "Chinachu/0.9.5-gamma.0 (scheduler) MirakurunClient/2.5.7 Node/v6.9.2 (linux)"; got != want {
+		t.Errorf("user agent is %v, want %v", got, want)
+	}
+}
+
+func TestClient_SubscribeLog(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/log/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		io.WriteString(w, "2018-01-21T08:00:00.153+09:00 info: closed\n")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	r, _, err := c.SubscribeLog(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if !r.Next() {
+		t.Fatal(r.Err())
+	}
+
+	if got, want := r.Entry().Message, "closed"; got != want {
+		t.Errorf("message is %v, want %v", got, want)
+	}
+}
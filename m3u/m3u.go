@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+/*
+Package m3u writes Mirakurun services as an M3U8 playlist for IPTV players.
+
+The channel IDs of the playlist are the ones of package xmltv, so that players
+can join the playlist and the XMLTV document.
+*/
+package m3u // import "ykzts.com/x/mirakurun/m3u"
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"ykzts.com/x/mirakurun"
+	"ykzts.com/x/mirakurun/xmltv"
+)
+
+// Options specifies the optional parameters to the Write function and the Build function.
+type Options struct {
+	// Decode makes the stream URLs request decoded streams.
+	Decode bool
+
+	// Priority is sent as the X-Mirakurun-Priority header by the players which
+	// support the #EXTHTTP tag. It is omitted if zero, the default priority.
+	Priority int
+
+	// XMLTVURL is the URL of the XMLTV document written as the url-tvg attribute.
+	XMLTVURL string
+
+	// Services specifies the services to be fetched by the Build function.
+	Services *mirakurun.ServicesListOptions
+
+	// BaseURL, if not nil, is used by the Build function instead of Client.BaseURL
+	// to resolve the stream and logo URLs. It must be set for a client connected
+	// by WithUnixSocket, whose BaseURL is not reachable from the players.
+	BaseURL *url.URL
+}
+
+// Write writes a playlist of the services to w. The stream and logo URLs are
+// resolved against baseURL, such as Client.BaseURL, so it must be reachable
+// from the players.
+func Write(w io.Writer, baseURL *url.URL, services []*mirakurun.Service, opt *Options) error {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprint(bw, "#EXTM3U")
+	if opt.XMLTVURL != "" {
+		fmt.Fprintf(bw, ` url-tvg="%s"`, attr(opt.XMLTVURL))
+	}
+	fmt.Fprint(bw, "\n")
+
+	for _, service := range services {
+		stream, err := baseURL.Parse(fmt.Sprintf("services/%d/stream", service.ID))
+		if err != nil {
+			return err
+		}
+		if opt.Decode {
+			stream.RawQuery = "decode=1"
+		}
+
+		fmt.Fprintf(bw, `#EXTINF:-1 tvg-id="%s" tvg-name="%s"`, xmltv.ChannelID(service.ID), attr(service.Name))
+
+		if service.HasLogoData {
+			logo, err := baseURL.Parse(fmt.Sprintf("services/%d/logo", service.ID))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(bw, ` tvg-logo="%s"`, attr(logo.String()))
+		}
+
+		if service.RemoteControlKeyID != 0 {
+			fmt.Fprintf(bw, ` tvg-chno="%d"`, service.RemoteControlKeyID)
+		}
+
+		if service.Channel.Type != "" {
+			fmt.Fprintf(bw, ` group-title="%s"`, attr(service.Channel.Type))
+		}
+
+		fmt.Fprintf(bw, ",%s\n", strings.Replace(service.Name, "\n", " ", -1))
+
+		if opt.Priority != 0 {
+			fmt.Fprintf(bw, "#EXTHTTP:{\"X-Mirakurun-Priority\":\"%d\"}\n", opt.Priority)
+		}
+
+		fmt.Fprintf(bw, "%s\n", stream)
+	}
+
+	return bw.Flush()
+}
+
+// Build fetches the services with c and writes a playlist of them to w.
+func Build(ctx context.Context, w io.Writer, c *mirakurun.Client, opt *Options) error {
+	baseURL := c.BaseURL
+	var servicesOpt *mirakurun.ServicesListOptions
+	if opt != nil {
+		servicesOpt = opt.Services
+		if opt.BaseURL != nil {
+			baseURL = opt.BaseURL
+		}
+	}
+
+	services, _, err := c.GetServices(ctx, servicesOpt)
+	if err != nil {
+		return err
+	}
+
+	return Write(w, baseURL, services, opt)
+}
+
+var attrReplacer = strings.NewReplacer(`"`, "'", "\n", " ", "\r", " ")
+
+// attr returns s to be written in a double-quoted attribute.
+func attr(s string) string {
+	return attrReplacer.Replace(s)
+}
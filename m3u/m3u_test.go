@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package m3u
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"ykzts.com/x/mirakurun"
+	"ykzts.com/x/mirakurun/internal/testutil"
+)
+
+func TestWrite(t *testing.T) {
+	var services []*mirakurun.Service
+	testutil.ReadJSON(t, "../testdata/services.json", &services)
+
+	baseURL, _ := url.Parse("http://127.0.0.1:40772/api/")
+	opt := &Options{Decode: true, Priority: 1, XMLTVURL: "http://127.0.0.1:8080/xmltv.xml"}
+
+	buf := new(bytes.Buffer)
+	if err := Write(buf, baseURL, services, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.CheckGolden(t, "testdata/playlist.golden.m3u8", buf.Bytes())
+}
+
+func TestBuild(t *testing.T) {
+	var channelType string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/services", func(w http.ResponseWriter, r *http.Request) {
+		channelType = r.URL.Query().Get("channel.type")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":3239123608,"serviceId":23608,"networkId":32391,"name":"TOKYO MX1","channel":{"type":"GR","channel":"16"}}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := mirakurun.NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	buf := new(bytes.Buffer)
+	opt := &Options{Services: &mirakurun.ServicesListOptions{ChannelType: "GR"}}
+	if err := Build(context.Background(), buf, c, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := channelType, "GR"; got != want {
+		t.Errorf("channel type is %v, want %v", got, want)
+	}
+
+	want := "#EXTM3U\n" +
+		`#EXTINF:-1 tvg-id="3239123608.mirakurun" tvg-name="TOKYO MX1" group-title="GR",TOKYO MX1` + "\n" +
+		server.URL + "/api/services/3239123608/stream\n"
+	if got := buf.String(); got != want {
+		t.Errorf("playlist is\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBuild_baseURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/services", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":3239123608,"serviceId":23608,"networkId":32391,"name":"TOKYO MX1","channel":{"type":"GR","channel":"16"}}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := mirakurun.NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	buf := new(bytes.Buffer)
+	opt := &Options{}
+	opt.BaseURL, _ = url.Parse("http://mirakurun.example.com:40772/api/")
+	if err := Build(context.Background(), buf, c, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "\nhttp://mirakurun.example.com:40772/api/services/3239123608/stream\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("playlist is\n%s\nwant the stream URL on BaseURL", buf)
+	}
+}
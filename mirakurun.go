@@ -26,12 +26,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -45,9 +48,24 @@ const (
 type Client struct {
 	client *http.Client
 
-	BaseURL   *url.URL
-	Priority  int
+	BaseURL  *url.URL
+	Priority int
+
+	// UserAgent is the name and version of the application, such as "Chinachu/0.9.5".
 	UserAgent string
+
+	// RetryPolicy makes Client.Do retry failed idempotent requests if not nil.
+	RetryPolicy *RetryPolicy
+
+	// Strict makes Client.Do and Client.SubscribeEvents reject response fields,
+	// including the ones of event data, that are not known to the destination type,
+	// which helps to detect Mirakurun API changes.
+	Strict bool
+
+	// unixSocket and timeout are set by WithUnixSocket and WithTimeout, and
+	// applied to the HTTP client by New after all options.
+	unixSocket string
+	timeout    *time.Duration
 }
 
 func addOptions(s string, opt interface{}) (string, error) {
@@ -75,10 +93,136 @@ func NewClient() *Client {
 	return &Client{client: httpClient, BaseURL: baseURL}
 }
 
+// A ClientOption configures a Client returned by New.
+type ClientOption func(*Client) error
+
+// New returns a new Mirakurun API client configured by opts.
+// Options are applied in order, except that the Unix domain socket and the timeout
+// are applied to the HTTP client last, whichever order WithHTTPClient is given in.
+func New(opts ...ClientOption) (*Client, error) {
+	c := NewClient()
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.unixSocket != "" || c.timeout != nil {
+		httpClient := *c.client
+		if c.unixSocket != "" {
+			httpClient.Transport = unixTransport(httpClient.Transport, c.unixSocket)
+		}
+		if c.timeout != nil {
+			httpClient.Timeout = *c.timeout
+		}
+		c.client = &httpClient
+	}
+
+	return c, nil
+}
+
+// WithBaseURL sets the base URL of the Mirakurun API, which must have a trailing slash.
+// A "http+unix" URL such as "http+unix:///var/run/mirakurun.sock/api/" connects to
+// the Unix domain socket as WithUnixSocket does.
+func WithBaseURL(rawurl string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return err
+		}
+
+		if err := checkBaseURL(u); err != nil {
+			return err
+		}
+
+		if u.Scheme != unixScheme {
+			c.BaseURL = u
+
+			return nil
+		}
+
+		path, u, err := splitUnixURL(u)
+		if err != nil {
+			return err
+		}
+
+		c.BaseURL = u
+
+		return WithUnixSocket(path)(c)
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send API requests.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("mirakurun: HTTP client must not be nil")
+		}
+
+		c.client = httpClient
+
+		return nil
+	}
+}
+
+// WithPriority sets the priority sent as the X-Mirakurun-Priority header.
+func WithPriority(priority int) ClientOption {
+	return func(c *Client) error {
+		c.Priority = priority
+
+		return nil
+	}
+}
+
+// WithUserAgent sets the name and version of the application, such as "Chinachu/0.9.5",
+// which is sent in the User-Agent header followed by the library and Go versions.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+
+		return nil
+	}
+}
+
+// WithTimeout sets the time limit for requests on a copy of the HTTP client,
+// so that a client given to WithHTTPClient is not modified.
+// The limit includes reading the response body, so it also bounds streams.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("mirakurun: timeout must not be negative, but %v is", timeout)
+		}
+
+		c.timeout = &timeout
+
+		return nil
+	}
+}
+
+// userAgent returns the User-Agent header value for the application name app,
+// such as "Chinachu/0.9.5 go-mirakurun/1.0 Go/1.22.0".
+func userAgent(app string) string {
+	ua := fmt.Sprintf("%s Go/%s", defaultUserAgent, strings.TrimPrefix(runtime.Version(), "go"))
+	if app == "" {
+		return ua
+	}
+
+	return fmt.Sprintf("%s %s", app, ua)
+}
+
+func checkBaseURL(u *url.URL) error {
+	if !strings.HasSuffix(u.Path, "/") {
+		return fmt.Errorf("mirakurun: BaseURL must have a trailing slash, but %q does not", u)
+	}
+
+	return nil
+}
+
 // NewRequest creates an API request.
 func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
-	if !strings.HasSuffix(c.BaseURL.Path, "/") {
-		return nil, fmt.Errorf("mirakurun: BaseURL must have a trailing slash, but %q does not", c.BaseURL)
+	if err := checkBaseURL(c.BaseURL); err != nil {
+		return nil, err
 	}
 
 	u, err := c.BaseURL.Parse(urlStr)
@@ -102,11 +246,7 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		return nil, err
 	}
 
-	if c.UserAgent == "" {
-		req.Header.Set("User-Agent", defaultUserAgent)
-	} else {
-		req.Header.Set("User-Agent", fmt.Sprintf("%s %s", c.UserAgent, defaultBaseURL))
-	}
+	req.Header.Set("User-Agent", userAgent(c.UserAgent))
 
 	req.Header.Set("X-Mirakurun-Priority", strconv.Itoa(c.Priority))
 
@@ -118,27 +258,154 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 }
 
 // Do sends an API requests and returns the API response.
+// The response body is decoded as JSON into v, or copied as is into v if it is
+// an io.Writer, such as for the log.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
 	req = req.WithContext(ctx)
 
-	resp, err := c.client.Do(req)
+	p := c.RetryPolicy
+	if ctx.Value(noRetryContextKey) != nil {
+		p = nil
+	}
+
+	resp, err := c.sendWithRetry(req, p)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode > 202 {
-		return resp, fmt.Errorf("mirakurun: %s", resp.Status)
+	if err := checkResponse(resp); err != nil {
+		return resp, err
 	}
 
-	if v != nil {
+	if w, ok := v.(io.Writer); ok {
+		if _, err := io.Copy(w, resp.Body); err != nil {
+			return resp, err
+		}
+	} else if v != nil {
 		dec := json.NewDecoder(resp.Body)
-		dec.Decode(v)
+		if c.Strict {
+			dec.DisallowUnknownFields()
+		}
+
+		if err := dec.Decode(v); err != nil {
+			return resp, newDecodeError(req, dec, err)
+		}
 	}
 
 	return resp, nil
 }
 
+// DecodeError reports an error caused by decoding an API response.
+type DecodeError struct {
+	Method string
+	URL    string
+	Offset int64
+	Err    error
+}
+
+func newDecodeError(req *http.Request, dec *json.Decoder, err error) *DecodeError {
+	offset := dec.InputOffset()
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		offset = e.Offset
+	case *json.UnmarshalTypeError:
+		offset = e.Offset
+	}
+
+	return &DecodeError{Method: req.Method, URL: req.URL.String(), Offset: offset, Err: err}
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("mirakurun: cannot decode response of %s %s at offset %d: %v", e.Method, e.URL, e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying decoding error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorResponse reports an error caused by an API request.
+type ErrorResponse struct {
+	Response *http.Response `json:"-"`
+
+	StatusCode int           `json:"code"`
+	Reason     string        `json:"reason,omitempty"`
+	Errors     []ErrorDetail `json:"errors,omitempty"`
+}
+
+// ErrorDetail represents a Mirakurun validation error.
+type ErrorDetail struct {
+	ErrorCode string `json:"errorCode,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Location  string `json:"location,omitempty"`
+}
+
+func (r *ErrorResponse) Error() string {
+	status := fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode))
+	if r.Response != nil {
+		status = r.Response.Status
+	}
+
+	msg := fmt.Sprintf("mirakurun: %s", status)
+	if r.Reason != "" && r.Reason != http.StatusText(r.StatusCode) {
+		msg = fmt.Sprintf("%s: %s", msg, r.Reason)
+	}
+
+	for _, e := range r.Errors {
+		if e.Location != "" {
+			msg = fmt.Sprintf("%s; %s: %s", msg, e.Location, e.Message)
+		} else {
+			msg = fmt.Sprintf("%s; %s", msg, e.Message)
+		}
+	}
+
+	return msg
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 202 {
+		return nil
+	}
+
+	errorResponse := &ErrorResponse{Response: resp}
+	data, err := io.ReadAll(resp.Body)
+	if err == nil && len(data) > 0 {
+		json.Unmarshal(data, errorResponse)
+	}
+
+	if errorResponse.StatusCode == 0 {
+		errorResponse.StatusCode = resp.StatusCode
+	}
+
+	return errorResponse
+}
+
+func hasStatusCode(err error, code int) bool {
+	var errorResponse *ErrorResponse
+	if !errors.As(err, &errorResponse) {
+		return false
+	}
+
+	return errorResponse.StatusCode == code
+}
+
+// IsNotFound reports whether err is an ErrorResponse for the 404 status.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an ErrorResponse for the 409 status.
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsTunerUnavailable reports whether err is an ErrorResponse for the 503 status,
+// which Mirakurun returns when no tuner is available for the request.
+func IsTunerUnavailable(err error) bool {
+	return hasStatusCode(err, http.StatusServiceUnavailable)
+}
+
 func (c *Client) requestStream(ctx context.Context, method string, u string) (io.ReadCloser, *http.Response, error) {
 	req, err := c.NewRequest(method, u, nil)
 	if err != nil {
@@ -146,14 +413,14 @@ func (c *Client) requestStream(ctx context.Context, method string, u string) (io
 	}
 
 	req = req.WithContext(ctx)
-	resp, err := c.client.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 202 {
+	if err := checkResponse(resp); err != nil {
 		resp.Body.Close()
-		return nil, resp, fmt.Errorf("mirakurun: %s", resp.Status)
+		return nil, resp, err
 	}
 
 	return resp.Body, resp, nil
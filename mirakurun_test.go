@@ -28,7 +28,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -39,6 +42,67 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNew(t *testing.T) {
+	httpClient := &http.Client{}
+	c, err := New(
+		WithBaseURL("http://192.168.0.5:40772/api/"),
+		WithHTTPClient(httpClient),
+		WithPriority(2),
+		WithUserAgent("Chinachu/0.9.5"),
+		WithTimeout(10*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := c.BaseURL.String(), "http://192.168.0.5:40772/api/"; got != want {
+		t.Errorf("BaseURL is %v, want %v", got, want)
+	}
+
+	if got, want := c.Priority, 2; got != want {
+		t.Errorf("priority is %v, want %v", got, want)
+	}
+
+	if got, want := c.UserAgent, "Chinachu/0.9.5"; got != want {
+		t.Errorf("user agent is %v, want %v", got, want)
+	}
+
+	if got, want := c.client.Timeout, 10*time.Second; got != want {
+		t.Errorf("timeout is %v, want %v", got, want)
+	}
+
+	if got, want := httpClient.Timeout, time.Duration(0); got != want {
+		t.Errorf("given HTTP client timeout is %v, want %v", got, want)
+	}
+}
+
+func TestNew_timeoutBeforeHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+	c, err := New(WithTimeout(10*time.Second), WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := c.client.Timeout, 10*time.Second; got != want {
+		t.Errorf("timeout is %v, want %v", got, want)
+	}
+
+	if got, want := httpClient.Timeout, time.Duration(0); got != want {
+		t.Errorf("given HTTP client timeout is %v, want %v", got, want)
+	}
+}
+
+func TestNew_invalidBaseURL(t *testing.T) {
+	_, err := New(WithBaseURL("http://192.168.0.5:40772/api"))
+	if err == nil {
+		t.Fatal("New should returns error")
+	}
+
+	if got, want := err.Error(), `mirakurun: BaseURL must have a trailing slash, but "http://192.168.0.5:40772/api" does not`; got != want {
+		t.Errorf("error is %v, want %v", got, want)
+	}
+}
+
 func TestClient_NewRequest(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/channels", func(w http.ResponseWriter, r *http.Request) {
@@ -59,6 +123,22 @@ func TestClient_NewRequest(t *testing.T) {
 	if got, want := req.URL.String(), server.URL+"/api/channels"; got != want {
 		t.Errorf("request URL is %v, want %v", got, want)
 	}
+
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	if got, want := req.Header.Get("User-Agent"), "go-mirakurun/1.0 Go/"+goVersion; got != want {
+		t.Errorf("user agent is %v, want %v", got, want)
+	}
+
+	c.UserAgent = "Chinachu/0.9.5"
+
+	req, err = c.NewRequest("GET", "channels", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := req.Header.Get("User-Agent"), "Chinachu/0.9.5 go-mirakurun/1.0 Go/"+goVersion; got != want {
+		t.Errorf("user agent is %v, want %v", got, want)
+	}
 }
 
 func TestClient_Do(t *testing.T) {
@@ -113,4 +193,139 @@ func TestClient_Do_notFound(t *testing.T) {
 	if got, want := err.Error(), "mirakurun: 404 Not Found"; got != want {
 		t.Errorf("error is %v, want %v", got, want)
 	}
+
+	if !IsNotFound(err) {
+		t.Errorf("error %v should be not found", err)
+	}
+}
+
+func TestClient_Do_errorResponse(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/services/3239123608/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, `{"code":503,"reason":"Tuner Resource Unavailable"}`)
+	})
+	mux.HandleFunc("/api/services", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"code":400,"reason":"Bad Request","errors":[{"errorCode":"type.openapi.validation","message":"should be integer","location":"query"}]}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	_, _, err := c.GetServiceStream(context.Background(), 3239123608, true)
+	if !IsTunerUnavailable(err) {
+		t.Fatalf("error is %v, want tuner unavailable", err)
+	}
+
+	if got, want := err.Error(), "mirakurun: 503 Service Unavailable: Tuner Resource Unavailable"; got != want {
+		t.Errorf("error is %v, want %v", got, want)
+	}
+
+	_, _, err = c.GetServices(context.Background(), nil)
+	errorResponse, ok := err.(*ErrorResponse)
+	if !ok {
+		t.Fatalf("error is %T, want *ErrorResponse", err)
+	}
+
+	if got, want := len(errorResponse.Errors), 1; got != want {
+		t.Fatalf("errors length is %v, want %v", got, want)
+	}
+
+	if got, want := errorResponse.Errors[0].Location, "query"; got != want {
+		t.Errorf("error location is %v, want %v", got, want)
+	}
+
+	if IsNotFound(err) || IsConflict(err) {
+		t.Errorf("error %v should not match other status codes", err)
+	}
+}
+
+func TestErrorResponse_Error(t *testing.T) {
+	err := &ErrorResponse{StatusCode: http.StatusNotFound}
+	if got, want := err.Error(), "mirakurun: 404 Not Found"; got != want {
+		t.Errorf("error is %v, want %v", got, want)
+	}
+
+	err = &ErrorResponse{StatusCode: http.StatusConflict, Reason: "Already Exists"}
+	if got, want := err.Error(), "mirakurun: 409 Conflict: Already Exists"; got != want {
+		t.Errorf("error is %v, want %v", got, want)
+	}
+}
+
+func TestClient_Do_decodeError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"current":2}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	_, _, err := c.CheckVersion(context.Background())
+	decodeError, ok := err.(*DecodeError)
+	if !ok {
+		t.Fatalf("error is %T, want *DecodeError", err)
+	}
+
+	if got, want := decodeError.URL, server.URL+"/api/version"; got != want {
+		t.Errorf("error URL is %v, want %v", got, want)
+	}
+
+	if got, want := decodeError.Offset, int64(12); got != want {
+		t.Errorf("error offset is %v, want %v", got, want)
+	}
+}
+
+func TestClient_Do_emptyBody(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	_, _, err := c.CheckVersion(context.Background())
+	decodeError, ok := err.(*DecodeError)
+	if !ok {
+		t.Fatalf("error is %T, want *DecodeError", err)
+	}
+
+	if got, want := decodeError.Err, io.EOF; got != want {
+		t.Errorf("underlying error is %v, want %v", got, want)
+	}
+}
+
+func TestClient_Do_strict(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"current":"2.5.7","latest":"2.5.7","channel":"stable"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	if _, _, err := c.CheckVersion(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Strict = true
+
+	_, _, err := c.CheckVersion(context.Background())
+	if _, ok := err.(*DecodeError); !ok {
+		t.Fatalf("error is %v, want *DecodeError", err)
+	}
 }
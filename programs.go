@@ -23,10 +23,14 @@
 package mirakurun
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // Program represents a Mirakurun program.
@@ -36,22 +40,73 @@ type Program struct {
 	ServiceID int       `json:"serviceId"`
 	NetworkID int       `json:"networkId"`
 	StartAt   Timestamp `json:"startAt"`
-	Duration  int       `json:"duration"`
+	Duration  Duration  `json:"duration"`
 	IsFree    bool      `json:"isFree"`
 
+	// IsPresentFollowing is set if the program is decoded from the EIT[p/f],
+	// which describes the present and the following programs of the service.
+	IsPresentFollowing bool `json:"_pf,omitempty"`
+
 	Name        string         `json:"name,omitempty"`
 	Description string         `json:"description,omitempty"`
 	Genres      []ProgramGenre `json:"genres,omitempty"`
 	Video       ProgramVideo   `json:"video,omitempty"`
 	Audio       ProgramAudio   `json:"audio,omitempty"`
+	Audios      []ProgramAudio `json:"audios,omitempty"`
 
 	Series ProgramSeries `json:"series,omitempty"`
 
-	Extended map[string]string `json:"extended,omitempty"`
+	Extended ProgramExtended `json:"extended,omitempty"`
 
 	RelatedItems []ProgramRelatedItem `json:"relatedItems,omitempty"`
 }
 
+// ServiceItemID returns the Mirakurun service ID of the program, such as 3239123608,
+// which is the ID field of the Service.
+func (p *Program) ServiceItemID() int {
+	return p.NetworkID*100000 + p.ServiceID
+}
+
+// Length returns the length of the program.
+func (p *Program) Length() time.Duration {
+	return p.Duration.Duration
+}
+
+// EndAt returns the time when the program ends.
+func (p *Program) EndAt() time.Time {
+	return p.StartAt.Add(p.Duration.Duration)
+}
+
+// IsAiringAt reports whether the program is on air at t.
+func (p *Program) IsAiringAt(t time.Time) bool {
+	return !t.Before(p.StartAt.Time) && t.Before(p.EndAt())
+}
+
+// HasEndedAt reports whether the program has ended at t.
+func (p *Program) HasEndedAt(t time.Time) bool {
+	return !t.Before(p.EndAt())
+}
+
+// Overlaps reports whether the program and other are on air at the same time.
+func (p *Program) Overlaps(other *Program) bool {
+	return p.StartAt.Before(other.EndAt()) && other.StartAt.Before(p.EndAt())
+}
+
+// FullDescription returns the description followed by the extended items,
+// each of which is written as its key and value lines, separated by blank lines.
+func (p *Program) FullDescription() string {
+	parts := []string{}
+	if p.Description != "" {
+		parts = append(parts, p.Description)
+	}
+
+	for _, item := range p.Extended {
+		parts = append(parts, item.Key+"\n"+item.Value)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
 // ProgramGenre represents a Mirakurun program genre.
 type ProgramGenre struct {
 	Level1      int `json:"lv1"`
@@ -71,8 +126,11 @@ type ProgramVideo struct {
 
 // ProgramAudio represents a Mirakurun program audio.
 type ProgramAudio struct {
-	SamplingRate  int `json:"samplingRate"`
-	ComponentType int `json:"componentType"`
+	SamplingRate  int      `json:"samplingRate"`
+	ComponentType int      `json:"componentType"`
+	ComponentTag  int      `json:"componentTag,omitempty"`
+	IsMain        bool     `json:"isMain,omitempty"`
+	Langs         []string `json:"langs,omitempty"`
 }
 
 // ProgramSeries represents a Mirakurun program series.
@@ -86,11 +144,113 @@ type ProgramSeries struct {
 	Name        string    `json:"name"`
 }
 
+// ProgramExtended represents the extended event items of a Mirakurun program,
+// such as "番組内容" and "出演者", in the order of the document.
+type ProgramExtended []ProgramExtendedItem
+
+// ProgramExtendedItem represents an extended event item of a Mirakurun program.
+type ProgramExtendedItem struct {
+	Key   string
+	Value string
+}
+
+// Get returns the value for key.
+func (e ProgramExtended) Get(key string) (string, bool) {
+	for _, item := range e {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Map returns the items as a map, which does not keep the order.
+func (e ProgramExtended) Map() map[string]string {
+	m := make(map[string]string, len(e))
+	for _, item := range e {
+		m[item.Key] = item.Value
+	}
+
+	return m
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e ProgramExtended) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, item := range e {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(item.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *ProgramExtended) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok == nil {
+		*e = nil
+		return nil
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("mirakurun: extended must be an object, but got %v", tok)
+	}
+
+	items := ProgramExtended{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		var value string
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+
+		items = append(items, ProgramExtendedItem{Key: tok.(string), Value: value})
+	}
+
+	*e = items
+
+	return nil
+}
+
 // ProgramRelatedItem represents a Mirakurun program related item.
 type ProgramRelatedItem struct {
-	NetworkID int `json:"networkId"`
-	ServiceID int `json:"serviceId"`
-	EventID   int `json:"eventId"`
+	// Type is "shared", "relay" or "movement".
+	Type      string `json:"type,omitempty"`
+	NetworkID int    `json:"networkId,omitempty"`
+	ServiceID int    `json:"serviceId"`
+	EventID   int    `json:"eventId"`
 }
 
 // ProgramsListOptions ...
@@ -24,10 +24,13 @@ package mirakurun
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestClient_GetPrograms(t *testing.T) {
@@ -64,6 +67,8 @@ func TestClient_GetProgram(t *testing.T) {
 	c := NewClient()
 	c.BaseURL, _ = url.Parse(server.URL + "/api/")
 
+	c.Strict = true
+
 	program, _, err := c.GetProgram(context.Background(), 40010310979)
 	if err != nil {
 		t.Fatal(err)
@@ -72,4 +77,148 @@ func TestClient_GetProgram(t *testing.T) {
 	if got, want := program.Name, "Cardcaptor Sakura: Clear Card ep. 3"; got != want {
 		t.Errorf("program name is %v, want %v", got, want)
 	}
+
+	if !program.IsPresentFollowing {
+		t.Error("program should be present or following")
+	}
+
+	if got, want := len(program.Audios), 2; got != want {
+		t.Fatalf("audios length is %v, want %v", got, want)
+	}
+
+	if got, want := program.Audios[1].Langs, []string{"jpn", "eng"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("audio langs are %v, want %v", got, want)
+	}
+
+	if !program.Audios[0].IsMain || program.Audios[1].IsMain {
+		t.Error("only the first audio should be main")
+	}
+
+	if got, want := program.Series.LastEpisode, 22; got != want {
+		t.Errorf("series last episode is %v, want %v", got, want)
+	}
+
+	if got, want := program.RelatedItems[1].Type, "relay"; got != want {
+		t.Errorf("related item type is %v, want %v", got, want)
+	}
+
+	if got, want := program.RelatedItems[1].NetworkID, 4; got != want {
+		t.Errorf("related item network ID is %v, want %v", got, want)
+	}
+}
+
+func TestProgramExtended(t *testing.T) {
+	data := `{"extended":{"番組内容":"Deleted for copyright.","出演者":"Sakura","原作":"CLAMP"}}`
+
+	program := new(Program)
+	if err := json.Unmarshal([]byte(data), program); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, item := range program.Extended {
+		keys = append(keys, item.Key)
+	}
+
+	if got, want := keys, []string{"番組内容", "出演者", "原作"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("keys are %v, want %v", got, want)
+	}
+
+	if got, _ := program.Extended.Get("原作"); got != "CLAMP" {
+		t.Errorf("value is %v, want %v", got, "CLAMP")
+	}
+
+	if _, ok := program.Extended.Get("音楽"); ok {
+		t.Error("value should not exist")
+	}
+
+	if got, want := len(program.Extended.Map()), 3; got != want {
+		t.Errorf("map length is %v, want %v", got, want)
+	}
+
+	encoded, err := json.Marshal(program.Extended)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(encoded), `{"番組内容":"Deleted for copyright.","出演者":"Sakura","原作":"CLAMP"}`; got != want {
+		t.Errorf("JSON is %v, want %v", got, want)
+	}
+}
+
+func TestProgram_EndAt(t *testing.T) {
+	program := &Program{
+		StartAt:  NewTimestamp(time.Date(2018, 1, 21, 7, 30, 0, 0, JST)),
+		Duration: Duration{25 * time.Minute},
+	}
+
+	if got, want := program.Length(), 25*time.Minute; got != want {
+		t.Errorf("length is %v, want %v", got, want)
+	}
+
+	if got, want := program.EndAt(), time.Date(2018, 1, 21, 7, 55, 0, 0, JST); !got.Equal(want) {
+		t.Errorf("end time is %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		t      time.Time
+		airing bool
+		ended  bool
+	}{
+		{time.Date(2018, 1, 21, 7, 29, 59, 0, JST), false, false},
+		{time.Date(2018, 1, 21, 7, 30, 0, 0, JST), true, false},
+		{time.Date(2018, 1, 21, 7, 54, 59, 0, JST), true, false},
+		{time.Date(2018, 1, 21, 7, 55, 0, 0, JST), false, true},
+	}
+
+	for _, test := range tests {
+		if got, want := program.IsAiringAt(test.t), test.airing; got != want {
+			t.Errorf("airing at %v is %v, want %v", test.t, got, want)
+		}
+
+		if got, want := program.HasEndedAt(test.t), test.ended; got != want {
+			t.Errorf("ended at %v is %v, want %v", test.t, got, want)
+		}
+	}
+}
+
+func TestProgram_Overlaps(t *testing.T) {
+	program := &Program{StartAt: NewTimestamp(time.Date(2018, 1, 21, 7, 30, 0, 0, JST)), Duration: Duration{30 * time.Minute}}
+
+	tests := []struct {
+		start    time.Time
+		duration time.Duration
+		want     bool
+	}{
+		{time.Date(2018, 1, 21, 7, 0, 0, 0, JST), 30 * time.Minute, false},
+		{time.Date(2018, 1, 21, 7, 0, 0, 0, JST), 31 * time.Minute, true},
+		{time.Date(2018, 1, 21, 7, 45, 0, 0, JST), 5 * time.Minute, true},
+		{time.Date(2018, 1, 21, 8, 0, 0, 0, JST), 30 * time.Minute, false},
+	}
+
+	for _, test := range tests {
+		other := &Program{StartAt: NewTimestamp(test.start), Duration: Duration{test.duration}}
+		if got, want := program.Overlaps(other), test.want; got != want {
+			t.Errorf("overlap with %v (%v) is %v, want %v", test.start, test.duration, got, want)
+		}
+	}
+}
+
+func TestProgram_FullDescription(t *testing.T) {
+	program := &Program{
+		Description: "Deleted for copyright.",
+		Extended: ProgramExtended{
+			{Key: "Program Content", Value: "Deleted for copyright."},
+			{Key: "Original Author", Value: "CLAMP"},
+		},
+	}
+
+	want := "Deleted for copyright.\n\nProgram Content\nDeleted for copyright.\n\nOriginal Author\nCLAMP"
+	if got := program.FullDescription(); got != want {
+		t.Errorf("full description is %q, want %q", got, want)
+	}
+
+	if got := (&Program{}).FullDescription(); got != "" {
+		t.Errorf("full description is %q, want empty", got)
+	}
 }
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy specifies how a Client retries idempotent requests.
+//
+// A request sent by Client.Do is retried when it fails with a connection error
+// or a 5xx status. Streams are never retried, so that a 503 status for no
+// available tuner is reported immediately.
+// GET and HEAD requests are always idempotent, and other requests are treated
+// as idempotent when they have an "Idempotency-Key" header, as net/http does.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one.
+	MaxAttempts int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between attempts.
+	// If zero, 500 milliseconds and 30 seconds are used. A Retry-After header
+	// longer than MaxBackoff is also shortened to it.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func (p *RetryPolicy) limits() (time.Duration, time.Duration) {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = defaultMinBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+
+	return min, max
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	min, max := p.limits()
+
+	d := min
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+
+	return false
+}
+
+// markIdempotent marks req as safe to retry; a nil header value is not sent.
+func markIdempotent(req *http.Request) {
+	req.Header["Idempotency-Key"] = nil
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if sec, err := strconv.Atoi(v); err == nil {
+		return time.Duration(sec) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+
+	return 0, false
+}
+
+// send sends req with the headers overridden by its context.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	return c.client.Do(withContextHeaders(req))
+}
+
+// sendWithRetry sends req like send, retrying it following p if not nil.
+func (c *Client) sendWithRetry(req *http.Request, p *RetryPolicy) (*http.Response, error) {
+	req = withContextHeaders(req)
+
+	if p == nil || p.MaxAttempts <= 1 || !isIdempotent(req) {
+		return c.client.Do(req)
+	}
+
+	ctx := req.Context()
+	r := req
+	for attempt := 1; ; attempt++ {
+		resp, err := c.client.Do(r)
+		if attempt >= p.MaxAttempts || ctx.Err() != nil || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait := p.backoff(attempt)
+		if d, ok := retryAfter(resp); ok {
+			if _, max := p.limits(); d > max {
+				d = max
+			}
+			wait = d
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return resp, err
+		}
+
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, err
+			}
+
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, err
+			}
+
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
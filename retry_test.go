@@ -0,0 +1,227 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestClient_RetryPolicy(t *testing.T) {
+	count := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count < 3 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeFile(w, r, "testdata/version.json")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	version, _, err := c.CheckVersion(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := version.Latest, "2.5.7"; got != want {
+		t.Errorf("version is %v, want %v", got, want)
+	}
+
+	if got, want := count, 3; got != want {
+		t.Errorf("request count is %v, want %v", got, want)
+	}
+}
+
+func TestClient_RetryPolicy_maxAttempts(t *testing.T) {
+	count := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		http.Error(w, "Bad Gateway", http.StatusBadGateway)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+
+	_, resp, err := c.CheckVersion(context.Background())
+	if err == nil {
+		t.Fatal("request should returns error")
+	}
+
+	if got, want := resp.StatusCode, http.StatusBadGateway; got != want {
+		t.Errorf("status code is %v, want %v", got, want)
+	}
+
+	if got, want := count, 2; got != want {
+		t.Errorf("request count is %v, want %v", got, want)
+	}
+}
+
+func TestClient_RetryPolicy_idempotentPut(t *testing.T) {
+	count := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/config/server", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count < 2 {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.Copy(w, r.Body)
+	})
+	mux.HandleFunc("/api/restart", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	serverConfig, _, err := c.UpdateServerConfig(context.Background(), &ServerConfig{Port: 40772})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := serverConfig.Port, 40772; got != want {
+		t.Errorf("server port is %v, want %v", got, want)
+	}
+
+	count = 0
+	if _, _, err := c.Restart(context.Background()); err == nil {
+		t.Fatal("request should returns error")
+	}
+
+	if got, want := count, 1; got != want {
+		t.Errorf("request count is %v, want %v", got, want)
+	}
+}
+
+func TestClient_RetryPolicy_deadline(t *testing.T) {
+	count := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 3}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, resp, err := c.CheckVersion(ctx)
+	if err == nil {
+		t.Fatal("request should returns error")
+	}
+
+	if got, want := resp.StatusCode, http.StatusServiceUnavailable; got != want {
+		t.Errorf("status code is %v, want %v", got, want)
+	}
+
+	if got, want := count, 1; got != want {
+		t.Errorf("request count is %v, want %v", got, want)
+	}
+}
+
+func TestClient_RetryPolicy_stream(t *testing.T) {
+	count := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/services/3239123608/stream", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, `{"code":503,"reason":"Tuner Resource Unavailable"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	_, _, err := c.GetServiceStream(context.Background(), 3239123608, true)
+	if !IsTunerUnavailable(err) {
+		t.Fatalf("error is %v, want tuner unavailable", err)
+	}
+
+	if got, want := count, 1; got != want {
+		t.Errorf("request count is %v, want %v", got, want)
+	}
+}
+
+func TestClient_RetryPolicy_retryAfter(t *testing.T) {
+	count := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count < 2 {
+			w.Header().Set("Retry-After", "3600")
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeFile(w, r, "testdata/version.json")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+	start := time.Now()
+	if _, _, err := c.CheckVersion(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("retry waited %v, want at most MaxBackoff", elapsed)
+	}
+
+	if got, want := count, 2; got != want {
+		t.Errorf("request count is %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package search_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"ykzts.com/x/mirakurun"
+	"ykzts.com/x/mirakurun/search"
+)
+
+func ExampleFind() {
+	c := mirakurun.NewClient()
+
+	q := &search.Query{
+		And:      []string{"カードキャプターさくら"},
+		Not:      []string{"総集編"},
+		NotFlags: search.Rerun,
+	}
+
+	programs, err := search.Find(context.Background(), c, q, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, program := range programs {
+		title, flags := search.ParseTitle(program.Name)
+		fmt.Println(title, flags.Has(search.New))
+	}
+}
+
+func ExampleParseTitle() {
+	title, flags := search.ParseTitle("【新】カードキャプターさくら　第１話[字]")
+	fmt.Println(title)
+	fmt.Println(flags.Has(search.New | search.Subtitled))
+	// Output:
+	// カードキャプターさくら 第1話
+	// true
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+/*
+Package search finds Mirakurun programs by keywords, tolerating the notational
+variants of Japanese program names.
+
+Names and keywords are compared after Normalize, so full-width and half-width
+letters, digits and katakana, as well as hiragana and katakana, match each
+other. The ARIB marks such as 【字】【再】【新】 are removed from the names and
+parsed into Flags by ParseTitle.
+*/
+package search // import "ykzts.com/x/mirakurun/search"
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"ykzts.com/x/mirakurun"
+)
+
+// Flags represents the ARIB marks of a program name.
+type Flags uint
+
+// The flags of the ARIB marks.
+const (
+	// New is the mark 新, the first episode of a series.
+	New Flags = 1 << iota
+	// Rerun is the mark 再.
+	Rerun
+	// Subtitled is the mark 字, closed captions.
+	Subtitled
+	// Final is the mark 終, the final episode of a series.
+	Final
+	// Live is the mark 生.
+	Live
+	// Bilingual is the mark 二, dual mono audio.
+	Bilingual
+	// AudioDescription is the mark 解.
+	AudioDescription
+	// Dubbed is the mark 吹.
+	Dubbed
+)
+
+// Has reports whether f has all of flags.
+func (f Flags) Has(flags Flags) bool {
+	return f&flags == flags
+}
+
+var markFlags = map[string]Flags{
+	"新": New,
+	"再": Rerun,
+	"字": Subtitled,
+	"終": Final,
+	"生": Live,
+	"二": Bilingual,
+	"解": AudioDescription,
+	"吹": Dubbed,
+}
+
+// marks are the ARIB additional symbols written in brackets, after Normalize.
+var marks = map[string]bool{
+	"hv": true, "sd": true, "p": true, "w": true, "mv": true, "手": true,
+	"字": true, "双": true, "デ": true, "s": true, "二": true, "多": true,
+	"解": true, "ss": true, "b": true, "n": true, "天": true, "交": true,
+	"映": true, "無": true, "料": true, "前": true, "後": true, "再": true,
+	"新": true, "初": true, "終": true, "生": true, "販": true, "声": true,
+	"吹": true, "ppv": true,
+}
+
+var markPattern = regexp.MustCompile(`[\[【]([^\[\]【】]{1,3})[\]】]`)
+
+// ParseTitle returns name without the ARIB marks, and the flags of the marks.
+// The title is normalized in width but keeps its case and kana.
+func ParseTitle(name string) (string, Flags) {
+	var flags Flags
+
+	name = markPattern.ReplaceAllStringFunc(expand(name), func(s string) string {
+		mark := markPattern.FindStringSubmatch(s)[1]
+		if !marks[strings.ToLower(mark)] {
+			return s
+		}
+
+		flags |= markFlags[mark]
+
+		return " "
+	})
+
+	return strings.Join(strings.Fields(name), " "), flags
+}
+
+// Normalize returns s in the form in which the keywords and the programs are
+// compared: NFKC, lower case, hiragana instead of katakana, and single spaces.
+func Normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'ァ' && r <= 'ヶ':
+			return r - 'ァ' + 'ぁ'
+		case r == '〜':
+			return '~'
+		case unicode.IsSpace(r):
+			return ' '
+		}
+
+		return unicode.ToLower(r)
+	}, expand(s))
+
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// widen returns s normalized in width as ParseTitle does, with single spaces.
+func widen(s string) string {
+	return strings.Join(strings.Fields(expand(s)), " ")
+}
+
+// expand applies NFKC to s, except that the squared ARIB symbols, such as 🈑,
+// are kept in brackets so that they can be told from the text.
+func expand(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		if isSquared(r) {
+			if d := norm.NFKC.String(string(r)); d != string(r) {
+				b.WriteString("[" + strings.Trim(d, "〔〕") + "]")
+				continue
+			}
+		}
+
+		b.WriteRune(r)
+	}
+
+	return norm.NFKC.String(b.String())
+}
+
+func isSquared(r rune) bool {
+	return (r >= 0x1F130 && r <= 0x1F1AD) || (r >= 0x1F200 && r <= 0x1F2FF)
+}
+
+// Query specifies the conditions of the programs to be found. The zero Query
+// matches all programs.
+type Query struct {
+	// And lists the keywords all of which must be contained.
+	And []string
+
+	// Or lists the keywords at least one of which must be contained.
+	Or []string
+
+	// Not lists the keywords none of which may be contained.
+	Not []string
+
+	// Regexp, if not nil, must match the title returned by ParseTitle, which is
+	// normalized in width but keeps its case and kana. If Description is set,
+	// the description and the extended items follow the title, each on its own line.
+	Regexp *regexp.Regexp
+
+	// Flags are the flags the program name must have.
+	Flags Flags
+
+	// NotFlags are the flags the program name must not have.
+	NotFlags Flags
+
+	// Description makes the keywords and the regexp also match the description
+	// and the extended items. By default, they match the title only.
+	Description bool
+}
+
+// Match reports whether the program matches q.
+//
+// Match prepares the keywords on every call; Filter should be used for many programs.
+func (q *Query) Match(p *mirakurun.Program) bool {
+	return q.matcher().match(p)
+}
+
+// Filter returns the programs matching q in order.
+func Filter(programs []*mirakurun.Program, q *Query) []*mirakurun.Program {
+	m := q.matcher()
+
+	var result []*mirakurun.Program
+	for _, p := range programs {
+		if m.match(p) {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+// Find fetches the programs with opt and returns the ones matching q.
+func Find(ctx context.Context, c *mirakurun.Client, q *Query, opt *mirakurun.ProgramsListOptions) ([]*mirakurun.Program, error) {
+	programs, _, err := c.GetPrograms(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return Filter(programs, q), nil
+}
+
+type matcher struct {
+	query        *Query
+	and, or, not []string
+}
+
+func (q *Query) matcher() *matcher {
+	return &matcher{
+		query: q,
+		and:   compactAll(q.And),
+		or:    compactAll(q.Or),
+		not:   compactAll(q.Not),
+	}
+}
+
+// compact removes the spaces from a normalized text, since they are used
+// inconsistently in Japanese program names.
+func compact(s string) string {
+	return strings.Replace(s, " ", "", -1)
+}
+
+func compactAll(keywords []string) []string {
+	var result []string
+	for _, keyword := range keywords {
+		if keyword = compact(Normalize(keyword)); keyword != "" {
+			result = append(result, keyword)
+		}
+	}
+
+	return result
+}
+
+func (m *matcher) match(p *mirakurun.Program) bool {
+	q := m.query
+
+	title, flags := ParseTitle(p.Name)
+	if !flags.Has(q.Flags) || flags&q.NotFlags != 0 {
+		return false
+	}
+
+	lines := []string{title}
+	if q.Description {
+		lines = append(lines, widen(p.Description))
+		for _, item := range p.Extended {
+			lines = append(lines, widen(item.Value))
+		}
+	}
+
+	if q.Regexp != nil && !q.Regexp.MatchString(strings.Join(lines, "\n")) {
+		return false
+	}
+
+	text := Normalize(strings.Join(lines, " "))
+	text = compact(text)
+
+	for _, keyword := range m.and {
+		if !strings.Contains(text, keyword) {
+			return false
+		}
+	}
+
+	for _, keyword := range m.not {
+		if strings.Contains(text, keyword) {
+			return false
+		}
+	}
+
+	if len(m.or) == 0 {
+		return true
+	}
+
+	for _, keyword := range m.or {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
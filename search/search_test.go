@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"ykzts.com/x/mirakurun"
+	"ykzts.com/x/mirakurun/internal/testutil"
+)
+
+func readPrograms(t *testing.T) []*mirakurun.Program {
+	var programs []*mirakurun.Program
+	testutil.ReadJSON(t, "testdata/programs.json", &programs)
+
+	return programs
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"ＣＬＡＭＰ　特集", "clamp 特集"},
+		{"第３話", "第3話"},
+		{"ｶｰﾄﾞｷｬﾌﾟﾀｰ", "かーどきゃぷたー"},
+		{"カードキャプター", "かーどきゃぷたー"},
+		{"さくら〜クリア～", "さくら~くりあ~"},
+		{"ニュース🈑", "にゅーす[字]"},
+		{"  a \t b\n", "a b"},
+	}
+
+	for _, test := range tests {
+		if got := Normalize(test.s); got != test.want {
+			t.Errorf("Normalize(%q) is %q, want %q", test.s, got, test.want)
+		}
+	}
+}
+
+func TestParseTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		flags Flags
+	}{
+		{"【新】カードキャプターさくら　第３話🈑", "カードキャプターさくら 第3話", New | Subtitled},
+		{"[再]ｶｰﾄﾞｷｬﾌﾟﾀｰさくら[字][デ]", "カードキャプターさくら", Rerun | Subtitled},
+		{"［終］ＣＬＡＭＰ特集【映画】", "CLAMP特集【映画】", Final},
+		{"ニュース🈢🈔", "ニュース", Live | Bilingual},
+		{"[SS]吹替版[吹]", "吹替版", Dubbed},
+		{"Part [A]", "Part [A]", 0},
+	}
+
+	for _, test := range tests {
+		title, flags := ParseTitle(test.name)
+		if title != test.title {
+			t.Errorf("title of %q is %q, want %q", test.name, title, test.title)
+		}
+
+		if flags != test.flags {
+			t.Errorf("flags of %q are %b, want %b", test.name, flags, test.flags)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	programs := readPrograms(t)
+
+	tests := []struct {
+		name  string
+		query *Query
+		want  []int
+	}{
+		{"zero", &Query{}, []int{40010310979, 40010310980, 40010310981, 40010310982}},
+		{"and", &Query{And: []string{"ｶｰﾄﾞｷｬﾌﾟﾀｰ", "さくら"}}, []int{40010310979, 40010310980}},
+		{"and spaces", &Query{And: []string{"さくら クリア"}}, []int{40010310979}},
+		{"or", &Query{Or: []string{"第3話", "clamp"}}, []int{40010310979, 40010310981}},
+		{"not", &Query{And: []string{"さくら"}, Not: []string{"クリアカード"}}, []int{40010310980}},
+		{"regexp", &Query{Regexp: regexp.MustCompile(`第[0-9]+話$`)}, []int{40010310979, 40010310980}},
+		{"regexp kana", &Query{Regexp: regexp.MustCompile(`カード.*第\d+話`)}, []int{40010310979, 40010310980}},
+		{"regexp case", &Query{Regexp: regexp.MustCompile(`clamp`)}, nil},
+		{"regexp case insensitive", &Query{Regexp: regexp.MustCompile(`(?i)clamp`)}, []int{40010310981}},
+		{"regexp description", &Query{Regexp: regexp.MustCompile(`(?m)^原作者CLAMP`), Description: true}, []int{40010310981}},
+		{"flags", &Query{Flags: Subtitled}, []int{40010310979, 40010310980, 40010310982}},
+		{"not flags", &Query{Flags: Subtitled, NotFlags: Rerun | Live}, []int{40010310979}},
+		{"title", &Query{And: []string{"さくら"}}, []int{40010310979, 40010310980}},
+		{"description", &Query{And: []string{"さくら"}, Description: true}, []int{40010310979, 40010310980, 40010310982}},
+		{"marks", &Query{And: []string{"字"}}, nil},
+	}
+
+	for _, test := range tests {
+		var got []int
+		for _, p := range Filter(programs, test.query) {
+			got = append(got, p.ID)
+		}
+
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	programs := readPrograms(t)
+	q := &Query{And: []string{"カードキャプター"}, NotFlags: Rerun}
+
+	if !q.Match(programs[0]) {
+		t.Errorf("%q does not match", programs[0].Name)
+	}
+
+	if q.Match(programs[1]) {
+		t.Errorf("%q matches", programs[1].Name)
+	}
+}
+
+func TestFind(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/programs", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("serviceId"), "103"; got != want {
+			t.Errorf("serviceId is %v, want %v", got, want)
+		}
+
+		http.ServeFile(w, r, "testdata/programs.json")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := mirakurun.NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	programs, err := Find(context.Background(), c, &Query{Flags: Final}, &mirakurun.ProgramsListOptions{ServiceID: 103})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(programs) != 1 {
+		t.Fatalf("found %d programs, want 1", len(programs))
+	}
+
+	if got, want := programs[0].ID, 40010310981; got != want {
+		t.Errorf("program ID is %v, want %v", got, want)
+	}
+}
@@ -67,6 +67,7 @@ func (c *Client) UpdateServerConfig(ctx context.Context, body *ServerConfig) (*S
 	if err != nil {
 		return nil, nil, err
 	}
+	markIdempotent(req)
 
 	config := new(ServerConfig)
 	resp, err := c.Do(ctx, req, config)
@@ -93,9 +94,39 @@ func (c *Client) GetLog(ctx context.Context) (*bytes.Buffer, *http.Response, err
 	return buf, resp, nil
 }
 
+// GetLogTail fetches a log and returns its last n lines. Mirakurun cannot limit
+// the log, so the whole log is downloaded and cut on the client.
+func (c *Client) GetLogTail(ctx context.Context, n int) (*bytes.Buffer, *http.Response, error) {
+	buf, resp, err := c.GetLog(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return tailLines(buf.Bytes(), n), resp, nil
+}
+
+// tailLines returns the last n lines of data.
+func tailLines(data []byte, n int) *bytes.Buffer {
+	if n <= 0 {
+		return new(bytes.Buffer)
+	}
+
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+
+	start := end
+	for ; n > 0 && start >= 0; n-- {
+		start = bytes.LastIndexByte(data[:start], '\n')
+	}
+
+	return bytes.NewBuffer(data[start+1:])
+}
+
 // GetLogStream fetches a log stream.
 func (c *Client) GetLogStream(ctx context.Context) (io.ReadCloser, *http.Response, error) {
-	return c.requestStream(ctx, "PUT", "log/stream")
+	return c.requestStream(ctx, "GET", "log/stream")
 }
 
 // Version represents a Mirakurun version.
@@ -138,7 +169,7 @@ func (c *Client) UpdateVersion(ctx context.Context, opt *VersionUpdateOptions) (
 	}
 
 	req = req.WithContext(ctx)
-	resp, err := c.client.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, resp, err
 	}
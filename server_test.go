@@ -28,6 +28,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -94,8 +96,9 @@ func TestClient_GetLog(t *testing.T) {
 
 	c := NewClient()
 	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.Strict = true
 
-	_, resp, err := c.GetLog(context.Background())
+	buf, resp, err := c.GetLog(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -103,13 +106,66 @@ func TestClient_GetLog(t *testing.T) {
 	if got, want := resp.Header.Get("Content-Type"), "text/plain; charset=utf-8"; got != want {
 		t.Errorf("content type is %v, want %v", got, want)
 	}
+
+	data, err := os.ReadFile("testdata/log.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buf.String(), string(data); got != want {
+		t.Errorf("log is %q, want %q", got, want)
+	}
+}
+
+func TestClient_GetLogTail(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/log", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/log.txt")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	data, err := os.ReadFile("testdata/log.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+
+	tests := []struct {
+		tail int
+		want string
+	}{
+		{0, ""},
+		{1, lines[2]},
+		{2, lines[1] + lines[2]},
+		{10, string(data)},
+	}
+
+	for _, test := range tests {
+		buf, _, err := c.GetLogTail(context.Background(), test.tail)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := buf.String(), test.want; got != want {
+			t.Errorf("log of tail %d is %q, want %q", test.tail, got, want)
+		}
+	}
 }
 
 func TestClient_GetLogStream(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/log/stream", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.Header().Set("Content-Type", "text/plain")
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
 		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
-		w.WriteHeader(http.StatusOK)
+		http.ServeFile(w, r, "testdata/log.txt")
 	})
 	server := httptest.NewServer(mux)
 	defer server.Close()
@@ -126,6 +182,15 @@ func TestClient_GetLogStream(t *testing.T) {
 	if got, want := resp.StatusCode, http.StatusOK; got != want {
 		t.Errorf("status code is %v, want %v", got, want)
 	}
+
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Count(string(data), "\n"), 3; got != want {
+		t.Errorf("line count is %v, want %v", got, want)
+	}
 }
 
 func TestClient_CheckVersion(t *testing.T) {
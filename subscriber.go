@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SubscriberState represents a connection state of a Subscriber.
+type SubscriberState int
+
+// The connection states of a Subscriber.
+const (
+	SubscriberConnecting SubscriberState = iota
+	SubscriberConnected
+	SubscriberDisconnected
+)
+
+func (s SubscriberState) String() string {
+	switch s {
+	case SubscriberConnecting:
+		return "connecting"
+	case SubscriberConnected:
+		return "connected"
+	case SubscriberDisconnected:
+		return "disconnected"
+	}
+
+	return fmt.Sprintf("SubscriberState(%d)", int(s))
+}
+
+// A Subscriber keeps receiving events from a Mirakurun events stream.
+//
+// When the stream ends, the Subscriber reconnects with backoff and replays the events
+// history from the time of the last received event, or of the first connection if
+// none, so that events which occurred while disconnected are not lost. Events already
+// received are not delivered again.
+type Subscriber struct {
+	client *Client
+	opt    *EventsListOptions
+
+	// Since, if not zero, makes the first connection also replay the events
+	// history from that time, such as the time when the state kept in sync by
+	// the events was fetched.
+	Since time.Time
+
+	// RetryPolicy controls the backoff between reconnections. If nil or its
+	// MaxAttempts is zero, the Subscriber reconnects until the context is done.
+	RetryPolicy *RetryPolicy
+
+	// Filters makes the Subscriber skip events that do not match all of them.
+	Filters []EventFilter
+
+	// OnStateChange is called when the connection state changes, with the error
+	// that caused the disconnection if any.
+	OnStateChange func(state SubscriberState, err error)
+
+	last time.Time
+	seen map[string]time.Time
+}
+
+// NewSubscriber returns a new Subscriber for the events matching opt.
+func NewSubscriber(c *Client, opt *EventsListOptions) *Subscriber {
+	return &Subscriber{client: c, opt: opt, seen: map[string]time.Time{}}
+}
+
+// Run receives events and calls handler for each of them until ctx is done, or until
+// reconnecting fails RetryPolicy.MaxAttempts times in a row. A reconnection counts as
+// successful once the events history missed while disconnected is replayed.
+//
+// The requests of the Subscriber are retried following RetryPolicy only, not
+// Client.RetryPolicy.
+func (s *Subscriber) Run(ctx context.Context, handler func(*Event)) error {
+	p := s.RetryPolicy
+	if p == nil {
+		p = &RetryPolicy{}
+	}
+
+	if s.last.IsZero() {
+		s.last = s.Since
+	}
+
+	attempt := 0
+	for {
+		s.setState(SubscriberConnecting, nil)
+
+		connected, err := s.receive(ctx, handler)
+		if ctx.Err() != nil {
+			s.setState(SubscriberDisconnected, ctx.Err())
+			return ctx.Err()
+		}
+		s.setState(SubscriberDisconnected, err)
+
+		if connected {
+			attempt = 0
+		}
+		attempt++
+
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// receive connects to the events stream once and delivers events until it ends.
+// It reports whether the connection succeeded, that is, the events history is
+// replayed if needed.
+func (s *Subscriber) receive(ctx context.Context, handler func(*Event)) (bool, error) {
+	ctx = withoutRetry(ctx)
+
+	stream, resp, err := s.client.SubscribeEvents(ctx, s.opt)
+	if err != nil {
+		return false, err
+	}
+	defer stream.Close()
+
+	if s.last.IsZero() {
+		// Nothing was missed yet, but a later reconnection has to replay the
+		// events since now even if none is received meanwhile.
+		s.last = responseTime(resp)
+	} else {
+		events, _, err := s.client.GetEvents(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		for _, event := range events {
+			if s.match(event) && matchEvent(event, s.Filters) {
+				s.deliver(event, handler)
+			}
+		}
+	}
+
+	s.setState(SubscriberConnected, nil)
+
+	stream.Filter(s.Filters...)
+	for stream.Next() {
+		s.deliver(stream.Event(), handler)
+	}
+
+	return true, stream.Err()
+}
+
+func (s *Subscriber) match(event *Event) bool {
+	if s.opt == nil {
+		return true
+	}
+
+	return (s.opt.Resource == "" || s.opt.Resource == event.Resource) &&
+		(s.opt.Type == "" || s.opt.Type == event.Type)
+}
+
+func (s *Subscriber) deliver(event *Event, handler func(*Event)) {
+	if event.Time.Before(s.last) {
+		return
+	}
+
+	data, _ := json.Marshal(event.Data)
+	key := fmt.Sprintf("%d/%s/%s/%s", event.Time.UnixNano(), event.Resource, event.Type, data)
+	if _, ok := s.seen[key]; ok {
+		return
+	}
+
+	if event.Time.After(s.last) {
+		s.last = event.Time.Time
+		for k, t := range s.seen {
+			if t.Before(s.last) {
+				delete(s.seen, k)
+			}
+		}
+	}
+	s.seen[key] = event.Time.Time
+
+	handler(event)
+}
+
+// responseTime returns the time of resp by the server clock, which the events are
+// stamped with, falling back to the local clock.
+func responseTime(resp *http.Response) time.Time {
+	if t, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		return t
+	}
+
+	return time.Now()
+}
+
+func (s *Subscriber) setState(state SubscriberState, err error) {
+	if s.OnStateChange != nil {
+		s.OnStateChange(state, err)
+	}
+}
@@ -0,0 +1,301 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSubscriber_Run(t *testing.T) {
+	connections := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		connections++
+		if connections == 2 {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Date", "Sat, 20 Jan 2018 22:29:59 GMT")
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[\n")
+		if connections == 1 {
+			io.WriteString(w, `{"resource":"program","type":"create","data":{"id":1},"time":1516487400000}`+"\n,\n")
+		} else {
+			io.WriteString(w, `{"resource":"program","type":"update","data":{"id":2},"time":1516487402000}`+"\n,\n")
+			io.WriteString(w, `{"resource":"program","type":"create","data":{"id":3},"time":1516487403000}`+"\n,\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	})
+	mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `[
+			{"resource":"program","type":"create","data":{"id":0},"time":1516487300000},
+			{"resource":"program","type":"create","data":{"id":1},"time":1516487400000},
+			{"resource":"tuner","type":"update","data":{"index":0},"time":1516487401000},
+			{"resource":"program","type":"update","data":{"id":2},"time":1516487402000}
+		]`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var states []SubscriberState
+	s := NewSubscriber(c, &EventsListOptions{Resource: "program"})
+	s.RetryPolicy = &RetryPolicy{MinBackoff: time.Millisecond}
+	s.OnStateChange = func(state SubscriberState, err error) {
+		states = append(states, state)
+	}
+
+	var ids []int
+	err := s.Run(ctx, func(event *Event) {
+		ids = append(ids, event.Program().ID)
+		if len(ids) == 3 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Fatalf("error is %v, want %v", err, context.Canceled)
+	}
+
+	if got, want := len(ids), 3; got != want {
+		t.Fatalf("events length is %v, want %v", got, want)
+	}
+
+	for i, id := range ids {
+		if got, want := id, i+1; got != want {
+			t.Errorf("program ID of event %d is %v, want %v", i, got, want)
+		}
+	}
+
+	if got, want := states[0], SubscriberConnecting; got != want {
+		t.Errorf("first state is %v, want %v", got, want)
+	}
+
+	if got, want := states[len(states)-1], SubscriberDisconnected; got != want {
+		t.Errorf("last state is %v, want %v", got, want)
+	}
+}
+
+func TestSubscriber_Run_noEvents(t *testing.T) {
+	connections := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		connections++
+		w.Header().Set("Date", "Sat, 20 Jan 2018 22:30:01 GMT")
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[\n")
+		if connections > 1 {
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	})
+	mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `[
+			{"resource":"program","type":"create","data":{"id":1},"time":1516487400000},
+			{"resource":"program","type":"update","data":{"id":2},"time":1516487402000}
+		]`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var states []SubscriberState
+	s := NewSubscriber(c, nil)
+	s.RetryPolicy = &RetryPolicy{MinBackoff: time.Millisecond}
+	s.OnStateChange = func(state SubscriberState, err error) {
+		states = append(states, state)
+	}
+
+	var ids []int
+	err := s.Run(ctx, func(event *Event) {
+		ids = append(ids, event.Program().ID)
+		cancel()
+	})
+	if err != context.Canceled {
+		t.Fatalf("error is %v, want %v", err, context.Canceled)
+	}
+
+	if len(ids) != 1 || ids[0] != 2 {
+		t.Errorf("program IDs are %v, want [2]", ids)
+	}
+
+	want := []SubscriberState{
+		SubscriberConnecting,
+		SubscriberConnected,
+		SubscriberDisconnected,
+		SubscriberConnecting,
+		SubscriberConnected,
+		SubscriberDisconnected,
+	}
+	if len(states) != len(want) {
+		t.Fatalf("states are %v, want %v", states, want)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Errorf("state %d is %v, want %v", i, states[i], want[i])
+		}
+	}
+}
+
+func TestSubscriber_Run_maxAttempts(t *testing.T) {
+	connections := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		connections++
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	s := NewSubscriber(c, nil)
+	s.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	err := s.Run(context.Background(), func(event *Event) {})
+	if err == nil {
+		t.Fatal("Run should returns error")
+	}
+
+	if got, want := connections, 3; got != want {
+		t.Errorf("connection count is %v, want %v", got, want)
+	}
+}
+
+func TestSubscriber_Run_replayFailure(t *testing.T) {
+	connections, replays := 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		connections++
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[\n")
+		io.WriteString(w, `{"resource":"program","type":"create","data":{"id":1},"time":1516487400000}`+"\n,\n")
+	})
+	mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+		replays++
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 5, MinBackoff: time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	connected := 0
+	s := NewSubscriber(c, nil)
+	s.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+	s.OnStateChange = func(state SubscriberState, err error) {
+		if state == SubscriberConnected {
+			connected++
+		}
+	}
+
+	err := s.Run(ctx, func(event *Event) {})
+	if !hasStatusCode(err, http.StatusInternalServerError) {
+		t.Fatalf("error is %v, want the replay error", err)
+	}
+
+	if got, want := connections, 3; got != want {
+		t.Errorf("connection count is %v, want %v", got, want)
+	}
+
+	if got, want := replays, 2; got != want {
+		t.Errorf("replay count is %v, want %v", got, want)
+	}
+
+	if got, want := connected, 1; got != want {
+		t.Errorf("connected count is %v, want %v", got, want)
+	}
+}
+
+func TestSubscriber_Run_since(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[\n")
+		io.WriteString(w, `{"resource":"program","type":"create","data":{"id":3},"time":1516487403000}`+"\n,\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `[
+			{"resource":"program","type":"create","data":{"id":1},"time":1516487400000},
+			{"resource":"program","type":"update","data":{"id":2},"time":1516487402000}
+		]`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := NewClient()
+	c.BaseURL, _ = url.Parse(server.URL + "/api/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := NewSubscriber(c, nil)
+	s.Since = time.Unix(1516487401, 0)
+
+	var ids []int
+	err := s.Run(ctx, func(event *Event) {
+		ids = append(ids, event.Program().ID)
+		if len(ids) == 2 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Fatalf("error is %v, want %v", err, context.Canceled)
+	}
+
+	if got, want := len(ids), 2; got != want {
+		t.Fatalf("events length is %v, want %v", got, want)
+	}
+
+	if ids[0] != 2 || ids[1] != 3 {
+		t.Errorf("program IDs are %v, want [2 3]", ids)
+	}
+}
@@ -27,19 +27,94 @@ import (
 	"time"
 )
 
-// Timestamp represents a Mirakurun timestamp.
+// JST is the Japan Standard Time zone, in which broadcast times are scheduled.
+var JST = time.FixedZone("JST", 9*60*60)
+
+// Timestamp represents a Mirakurun timestamp, which is encoded as the number of
+// milliseconds elapsed since January 1, 1970 UTC.
 type Timestamp struct {
 	time.Time
 }
 
+// NewTimestamp returns a Timestamp for t truncated to milliseconds.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{t.Truncate(time.Millisecond)}
+}
+
+// JST returns t in the Japan Standard Time zone.
+func (t Timestamp) JST() time.Time {
+	return t.In(JST)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The zero Timestamp is encoded as null.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return t.MarshalText()
+}
+
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		(*t).Time = time.Time{}
+		return nil
+	}
+
+	return t.UnmarshalText(data)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (t Timestamp) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return []byte{}, nil
+	}
+
+	return strconv.AppendInt(nil, t.UnixNano()/int64(time.Millisecond), 10), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (t *Timestamp) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		(*t).Time = time.Time{}
+		return nil
+	}
+
+	mSec, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	(*t).Time = time.Unix(0, mSec*int64(time.Millisecond))
+
+	return nil
+}
+
+// Duration represents a Mirakurun duration, which is encoded as a number of milliseconds.
+type Duration struct {
+	time.Duration
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(d.Duration/time.Millisecond), 10), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		(*d).Duration = 0
+		return nil
+	}
+
 	mSec, err := strconv.ParseInt(string(data), 10, 64)
 	if err != nil {
 		return err
 	}
 
-	(*t).Time = time.Unix(mSec/1000, 0)
+	(*d).Duration = time.Duration(mSec) * time.Millisecond
 
 	return nil
 }
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	var ts Timestamp
+	if err := json.Unmarshal([]byte("1516487400123"), &ts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := ts.UnixNano(), int64(1516487400123000000); got != want {
+		t.Errorf("timestamp is %v, want %v", got, want)
+	}
+
+	if err := json.Unmarshal([]byte("null"), &ts); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ts.IsZero() {
+		t.Errorf("timestamp is %v, want zero", ts)
+	}
+}
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	program := &Program{ID: 40010310979, StartAt: NewTimestamp(time.Unix(1516487400, 123456789))}
+
+	data, err := json.Marshal(program.StartAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(data), "1516487400123"; got != want {
+		t.Errorf("JSON is %v, want %v", got, want)
+	}
+
+	data, err = json.Marshal(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := new(Program)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.StartAt.Equal(program.StartAt.Time) {
+		t.Errorf("round-tripped timestamp is %v, want %v", decoded.StartAt, program.StartAt)
+	}
+
+	data, err = json.Marshal(Timestamp{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(data), "null"; got != want {
+		t.Errorf("JSON of zero timestamp is %v, want %v", got, want)
+	}
+}
+
+func TestTimestamp_JST(t *testing.T) {
+	ts := NewTimestamp(time.Date(2018, 1, 20, 22, 30, 0, 0, time.UTC))
+
+	if got, want := ts.JST().Format("2006-01-02 15:04 MST"), "2018-01-21 07:30 JST"; got != want {
+		t.Errorf("time in JST is %v, want %v", got, want)
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	var d Duration
+	if err := json.Unmarshal([]byte("1500000"), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := d.Duration, 25*time.Minute; got != want {
+		t.Errorf("duration is %v, want %v", got, want)
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(data), "1500000"; got != want {
+		t.Errorf("JSON is %v, want %v", got, want)
+	}
+}
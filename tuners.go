@@ -159,6 +159,7 @@ func (c *Client) UpdateTunersConfig(ctx context.Context, body TunersConfig) (Tun
 	if err != nil {
 		return nil, nil, err
 	}
+	markIdempotent(req)
 
 	var tuners TunersConfig
 	resp, err := c.Do(ctx, req, &tuners)
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const unixScheme = "http+unix"
+
+// WithUnixSocket makes the client talk to Mirakurun over the Unix domain socket at path,
+// such as /var/run/mirakurun.sock. The API path of the base URL is kept.
+//
+// If the HTTP client, given to WithHTTPClient in any order, uses an *http.Transport,
+// a copy of it is used to dial the socket; otherwise a copy of http.DefaultTransport is used.
+func WithUnixSocket(path string) ClientOption {
+	return func(c *Client) error {
+		if path == "" {
+			return fmt.Errorf("mirakurun: unix socket path must not be empty")
+		}
+
+		c.unixSocket = path
+
+		u := *c.BaseURL
+		u.Scheme = "http"
+		u.Host = "localhost"
+		c.BaseURL = &u
+
+		return nil
+	}
+}
+
+// unixTransport returns a copy of rt, or of http.DefaultTransport if rt is not
+// an *http.Transport, which dials the Unix domain socket at path.
+func unixTransport(rt http.RoundTripper, path string) *http.Transport {
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	}
+
+	return transport
+}
+
+// splitUnixURL splits a http+unix URL such as "http+unix:///var/run/mirakurun.sock/api/"
+// into the socket path, which ends with the first segment with the ".sock" suffix,
+// and the HTTP URL to request.
+func splitUnixURL(u *url.URL) (string, *url.URL, error) {
+	i := strings.Index(u.Path, ".sock/")
+	if u.Host != "" || i < 0 {
+		return "", nil, fmt.Errorf("mirakurun: %q does not contain a socket path", u)
+	}
+
+	return u.Path[:i+len(".sock")], &url.URL{Scheme: "http", Host: "localhost", Path: u.Path[i+len(".sock"):]}, nil
+}
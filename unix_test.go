@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mirakurun
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+func newUnixServer(t *testing.T, handler http.Handler) (string, func()) {
+	path := filepath.Join(t.TempDir(), "mirakurun.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &http.Server{Handler: handler}
+	go server.Serve(l)
+
+	return path, func() {
+		server.Close()
+	}
+}
+
+func TestWithUnixSocket(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/version.json")
+	})
+	path, closeServer := newUnixServer(t, mux)
+	defer closeServer()
+
+	c, err := New(WithUnixSocket(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, _, err := c.CheckVersion(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := version.Latest, "2.5.7"; got != want {
+		t.Errorf("version is %v, want %v", got, want)
+	}
+}
+
+func TestWithUnixSocket_beforeHTTPClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/version.json")
+	})
+	path, closeServer := newUnixServer(t, mux)
+	defer closeServer()
+
+	httpClient := &http.Client{Transport: &http.Transport{}}
+	c, err := New(WithUnixSocket(path), WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := c.CheckVersion(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if httpClient.Transport.(*http.Transport).DialContext != nil {
+		t.Error("given HTTP transport is modified")
+	}
+}
+
+func TestWithBaseURL_unix(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mirakurun/api/services/3239123608/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/MP2T")
+		w.WriteHeader(http.StatusOK)
+	})
+	path, closeServer := newUnixServer(t, mux)
+	defer closeServer()
+
+	c, err := New(WithBaseURL("http+unix://" + path + "/mirakurun/api/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := c.BaseURL.String(), "http://localhost/mirakurun/api/"; got != want {
+		t.Errorf("BaseURL is %v, want %v", got, want)
+	}
+
+	stream, _, err := c.GetServiceStream(context.Background(), 3239123608, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.Close()
+}
+
+func TestSplitUnixURL(t *testing.T) {
+	tests := []struct {
+		rawurl string
+		path   string
+		u      string
+	}{
+		{"http+unix:///var/run/mirakurun.sock/api/", "/var/run/mirakurun.sock", "http://localhost/api/"},
+		{"http+unix:///tmp/socks/mirakurun.sock/mirakurun/api/", "/tmp/socks/mirakurun.sock", "http://localhost/mirakurun/api/"},
+	}
+
+	for _, test := range tests {
+		u, _ := url.Parse(test.rawurl)
+		path, u, err := splitUnixURL(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := path, test.path; got != want {
+			t.Errorf("socket path of %v is %v, want %v", test.rawurl, got, want)
+		}
+
+		if got, want := u.String(), test.u; got != want {
+			t.Errorf("URL of %v is %v, want %v", test.rawurl, got, want)
+		}
+	}
+
+	if _, err := New(WithBaseURL("http+unix:///var/run/mirakurun/api/")); err == nil {
+		t.Error("New should returns error for a URL without socket path")
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+/*
+Package xmltv writes Mirakurun services and programs as an XMLTV document.
+
+The XMLTV format is described at http://wiki.xmltv.org/index.php/XMLTVFormat.
+*/
+package xmltv // import "ykzts.com/x/mirakurun/xmltv"
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"ykzts.com/x/mirakurun"
+)
+
+const timeLayout = "20060102150405 -0700"
+
+// ChannelID returns the XMLTV channel ID for the Mirakurun service ID id.
+func ChannelID(id int) string {
+	return fmt.Sprintf("%d.mirakurun", id)
+}
+
+type channel struct {
+	XMLName     xml.Name `xml:"channel"`
+	ID          string   `xml:"id,attr"`
+	DisplayName []text   `xml:"display-name"`
+	Icon        *icon    `xml:"icon,omitempty"`
+}
+
+type programme struct {
+	XMLName    xml.Name     `xml:"programme"`
+	Start      string       `xml:"start,attr"`
+	Stop       string       `xml:"stop,attr"`
+	Channel    string       `xml:"channel,attr"`
+	Title      text         `xml:"title"`
+	Desc       *text        `xml:"desc,omitempty"`
+	Categories []text       `xml:"category"`
+	EpisodeNum []episodeNum `xml:"episode-num"`
+	Video      *video       `xml:"video,omitempty"`
+}
+
+type text struct {
+	Lang  string `xml:"lang,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type icon struct {
+	Src string `xml:"src,attr"`
+}
+
+type episodeNum struct {
+	System string `xml:"system,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type video struct {
+	Aspect  string `xml:"aspect,omitempty"`
+	Quality string `xml:"quality,omitempty"`
+}
+
+// A Writer writes an XMLTV document.
+//
+// Channels must be written before programmes, and Close must be called to
+// finish the document.
+type Writer struct {
+	// BaseURL is the base URL of the Mirakurun API, such as Client.BaseURL,
+	// which is used for the URLs of the channel logos. If nil, logos are omitted.
+	BaseURL *url.URL
+
+	w       io.Writer
+	enc     *xml.Encoder
+	started bool
+	closed  bool
+}
+
+// NewWriter returns a new Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	return &Writer{w: w, enc: enc}
+}
+
+func (w *Writer) start() error {
+	if w.closed {
+		return errors.New("xmltv: write to closed Writer")
+	}
+
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	if _, err := io.WriteString(w.w, xml.Header+`<!DOCTYPE tv SYSTEM "xmltv.dtd">`+"\n"); err != nil {
+		return err
+	}
+
+	return w.enc.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "tv"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "generator-info-name"}, Value: "go-mirakurun"}},
+	})
+}
+
+// WriteChannel writes a channel for the service.
+func (w *Writer) WriteChannel(service *mirakurun.Service) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	c := &channel{
+		ID:          ChannelID(service.ID),
+		DisplayName: []text{{Lang: "ja", Value: service.Name}},
+	}
+
+	if w.BaseURL != nil && service.HasLogoData {
+		logo, err := w.BaseURL.Parse(fmt.Sprintf("services/%d/logo", service.ID))
+		if err != nil {
+			return err
+		}
+		c.Icon = &icon{Src: logo.String()}
+	}
+
+	return w.enc.Encode(c)
+}
+
+// WriteProgramme writes a programme for the program.
+func (w *Writer) WriteProgramme(program *mirakurun.Program) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	p := &programme{
+		Start:   program.StartAt.JST().Format(timeLayout),
+		Stop:    program.EndAt().In(mirakurun.JST).Format(timeLayout),
+		Channel: ChannelID(program.ServiceItemID()),
+		Title:   text{Lang: "ja", Value: program.Name},
+	}
+
+	if desc := program.FullDescription(); desc != "" {
+		p.Desc = &text{Lang: "ja", Value: desc}
+	}
+
+	p.Categories = categories(program)
+	p.EpisodeNum = episodeNums(program.Series)
+
+	if format, ok := program.Video.Format(); ok {
+		p.Video = &video{Aspect: strings.TrimPrefix(format.AspectRatio, ">")}
+		if format.Height >= 720 {
+			p.Video.Quality = "HDTV"
+		} else {
+			p.Video.Quality = "SDTV"
+		}
+	}
+
+	return w.enc.Encode(p)
+}
+
+// Close finishes the document. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	if err := w.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "tv"}}); err != nil {
+		return err
+	}
+
+	if err := w.enc.Flush(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w.w, "\n")
+	return err
+}
+
+// Write writes an XMLTV document of the services and programs, such as the ones
+// returned by Client.GetServices and Client.GetPrograms. The programs for the
+// services not in services are skipped.
+func Write(w io.Writer, baseURL *url.URL, services []*mirakurun.Service, programs []*mirakurun.Program) error {
+	xw := NewWriter(w)
+	xw.BaseURL = baseURL
+
+	ids := make(map[int]bool, len(services))
+	for _, service := range services {
+		ids[service.ID] = true
+		if err := xw.WriteChannel(service); err != nil {
+			return err
+		}
+	}
+
+	for _, program := range programs {
+		if !ids[program.ServiceItemID()] {
+			continue
+		}
+
+		if err := xw.WriteProgramme(program); err != nil {
+			return err
+		}
+	}
+
+	return xw.Close()
+}
+
+func categories(program *mirakurun.Program) []text {
+	var texts []text
+	for _, g := range program.MajorGenres() {
+		texts = append(texts, text{Lang: "ja", Value: g.Japanese()}, text{Lang: "en", Value: g.String()})
+	}
+
+	return texts
+}
+
+func episodeNums(series mirakurun.ProgramSeries) []episodeNum {
+	if series.Episode <= 0 {
+		return nil
+	}
+
+	ns := strconv.Itoa(series.Episode - 1)
+	if series.LastEpisode > 0 {
+		ns += "/" + strconv.Itoa(series.LastEpisode)
+	}
+
+	return []episodeNum{
+		{System: "xmltv_ns", Value: "." + ns + "."},
+		{System: "onscreen", Value: "#" + strconv.Itoa(series.Episode)},
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2018 Yamagishi Kazutoshi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package xmltv
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"testing"
+
+	"ykzts.com/x/mirakurun"
+	"ykzts.com/x/mirakurun/internal/testutil"
+)
+
+func TestWrite(t *testing.T) {
+	var services []*mirakurun.Service
+	testutil.ReadJSON(t, "../testdata/services.json", &services)
+
+	var programs []*mirakurun.Program
+	testutil.ReadJSON(t, "../testdata/epg_programs.json", &programs)
+
+	baseURL, _ := url.Parse("http://127.0.0.1:40772/api/")
+
+	buf := new(bytes.Buffer)
+	if err := Write(buf, baseURL, services, programs); err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.CheckGolden(t, "testdata/xmltv.golden.xml", buf.Bytes())
+}
+
+func TestWriter_closed(t *testing.T) {
+	w := NewWriter(io.Discard)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.WriteChannel(&mirakurun.Service{ID: 3239123608}); err == nil {
+		t.Error("WriteChannel should returns error after Close")
+	}
+}